@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// runBench implements `yule-log bench`: run a theme flat out against an
+// in-memory screen and report where each frame's time goes, as a
+// regression guard for the simulation and render path.
+func runBench(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	size := fs.String("size", "200x60", "Screen size as WIDTHxHEIGHT")
+	frames := fs.Int("frames", 1000, "Number of frames to run")
+	themeName := fs.String("theme", "fire", "Theme to run: "+themeNames())
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	width, height, err := parseSize(*size)
+	if err != nil {
+		return err
+	}
+	if *frames <= 0 {
+		return fmt.Errorf("--frames must be positive")
+	}
+	idx, ok := themeIndex(*themeName)
+	if !ok {
+		return fmt.Errorf("unknown theme %q", *themeName)
+	}
+
+	s := tcell.NewSimulationScreen("UTF-8")
+	if err := s.Init(); err != nil {
+		return err
+	}
+	defer s.Fini()
+	s.SetSize(width, height)
+
+	vis := themes[idx].newVisualization(visOptions{seed: 1, colors: screenColorMode(s), layout: themes[idx].layout})
+	vis.Resize(width, height)
+	cache := newFrameCache(width, height)
+	styled := &styledFrame{width: width, cells: make([]cell, width*height)}
+
+	var simulate, style, draw time.Duration
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for frame := 0; frame < *frames; frame++ {
+		t0 := time.Now()
+		vis.Step(frame)
+		t1 := time.Now()
+		styled.capture(vis)
+		t2 := time.Now()
+		renderFrame(s, cache, styled, width, height, 0, false)
+		s.Show()
+		t3 := time.Now()
+		simulate += t1.Sub(t0)
+		style += t2.Sub(t1)
+		draw += t3.Sub(t2)
+	}
+	total := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	n := float64(*frames)
+	perFrame := func(d time.Duration) float64 { return d.Seconds() * 1e6 / n }
+	fmt.Fprintf(w, "%s at %dx%d, %d frames\n", *themeName, width, height, *frames)
+	fmt.Fprintf(w, "  %.1f fps\n", n/total.Seconds())
+	fmt.Fprintf(w, "  %.1fµs per frame: %.1fµs simulate, %.1fµs style, %.1fµs draw\n",
+		perFrame(total), perFrame(simulate), perFrame(style), perFrame(draw))
+	fmt.Fprintf(w, "  %.1f allocations (%.0f bytes) per frame\n",
+		float64(after.Mallocs-before.Mallocs)/n, float64(after.TotalAlloc-before.TotalAlloc)/n)
+	return nil
+}
+
+// parseSize parses "WIDTHxHEIGHT".
+func parseSize(s string) (width, height int, err error) {
+	bad := fmt.Errorf("size: want WIDTHxHEIGHT, got %q", s)
+	w, h, ok := strings.Cut(s, "x")
+	if !ok {
+		return 0, 0, bad
+	}
+	if width, err = strconv.Atoi(w); err != nil || width <= 0 {
+		return 0, 0, bad
+	}
+	if height, err = strconv.Atoi(h); err != nil || height <= 0 {
+		return 0, 0, bad
+	}
+	return width, height, nil
+}
+
+// styledFrame is a Visualization frozen at one frame, so that the bench
+// can time working out every cell's glyph and style separately from
+// drawing them.
+type styledFrame struct {
+	width int
+	cells []cell
+}
+
+// capture records every cell of vis.
+func (f *styledFrame) capture(vis Visualization) {
+	height := len(f.cells) / f.width
+	for y := 0; y < height; y++ {
+		for x := 0; x < f.width; x++ {
+			ch, style := vis.Cell(x, y)
+			f.cells[y*f.width+x] = cell{ch: ch, style: style}
+		}
+	}
+}
+
+func (f *styledFrame) Resize(width, height int)        {}
+func (f *styledFrame) Step(frame int)                  {}
+func (f *styledFrame) HandleInput(ev tcell.Event) bool { return false }
+func (f *styledFrame) Cell(x, y int) (rune, tcell.Style) {
+	return f.cells[y*f.width+x].ch, f.cells[y*f.width+x].style
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunBench(t *testing.T) {
+	var out bytes.Buffer
+	if err := runBench([]string{"--size", "40x12", "--frames", "5"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"fire at 40x12, 5 frames", "fps", "simulate", "style", "draw", "allocations"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := runBench([]string{"--size", "40by12"}, &out); err == nil {
+		t.Errorf("expected an error for a bad size")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	if w, h, err := parseSize("200x60"); err != nil || w != 200 || h != 60 {
+		t.Fatalf("parseSize(200x60) = %d, %d, %v", w, h, err)
+	}
+	for _, bad := range []string{"200x60abc", "200x60x2", "200 x60", "x60", "200x", "0x60", "-5x60", "40by12"} {
+		if _, _, err := parseSize(bad); err == nil {
+			t.Errorf("parseSize(%q) accepted trailing or malformed input", bad)
+		}
+	}
+}
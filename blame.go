@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// blameSnippetLen caps how much of each line of code the blame ticker
+// shows.
+const blameSnippetLen = 60
+
+// blameLine is one line of `git blame` output.
+type blameLine struct {
+	number int
+	author string
+	time   time.Time
+	code   string
+}
+
+// blameTickerCards runs git blame on file in dir and returns a ticker card
+// for each line of code, over who last touched it and when.
+func blameTickerCards(dir, file string) ([]tickerCard, error) {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", file)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git blame: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git blame: %w", err)
+	}
+	cards := blameCards(parseBlame(string(out)), time.Now())
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("%s has no lines to blame", file)
+	}
+	return cards, nil
+}
+
+// parseBlame parses `git blame --line-porcelain` output.
+func parseBlame(out string) []blameLine {
+	var lines []blameLine
+	var cur blameLine
+	inHeader := false
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The line itself ends each entry.
+			cur.code = line[1:]
+			lines = append(lines, cur)
+			inHeader = false
+		case !inHeader:
+			// "<sha> <orig line> <final line> [<group size>]"
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			n, err := strconv.Atoi(fields[2])
+			if err != nil {
+				continue
+			}
+			cur = blameLine{number: n}
+			inHeader = true
+		case strings.HasPrefix(line, "author "):
+			cur.author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if secs, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				cur.time = time.Unix(secs, 0)
+			}
+		}
+	}
+	return lines
+}
+
+// blameCards turns blamed lines into ticker cards, skipping blank ones.
+func blameCards(lines []blameLine, now time.Time) []tickerCard {
+	var cards []tickerCard
+	for _, l := range lines {
+		code := strings.TrimSpace(strings.ReplaceAll(l.code, "\t", "    "))
+		if code == "" {
+			continue
+		}
+		cards = append(cards, tickerCard{
+			msg:    fmt.Sprintf("L%d  %s", l.number, truncate(code, blameSnippetLen)),
+			meta:   "by " + l.author + " " + relativeAge(l.time, now),
+			author: l.author,
+			when:   l.time,
+		})
+	}
+	return cards
+}
+
+// relativeAge describes how long before now t was, like git's "%ar".
+func relativeAge(t, now time.Time) string {
+	d := now.Sub(t)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit + " ago"
+		}
+		return strconv.Itoa(n) + " " + unit + "s ago"
+	}
+	switch days := int(d.Hours() / 24); {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	case days < 14:
+		return plural(days, "day")
+	case days < 60:
+		return plural(days/7, "week")
+	case days < 365:
+		return plural(days/30, "month")
+	default:
+		return plural(days/365, "year")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const samplePorcelain = `1f8616a71d30ac527afac8ab548dc19310d21065 1 1 2
+author Ada
+author-mail <ada@example.com>
+author-time 1700000000
+author-tz +0000
+summary first
+filename x.go
+	package main
+1f8616a71d30ac527afac8ab548dc19310d21065 2 2
+author Ada
+author-mail <ada@example.com>
+author-time 1700000000
+author-tz +0000
+summary first
+filename x.go
+	
+9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b 5 3 1
+author Grace Hopper
+author-mail <grace@example.com>
+author-time 1700086400
+author-tz +0000
+summary second
+filename x.go
+	func main() {}
+`
+
+func TestParseBlame(t *testing.T) {
+	lines := parseBlame(samplePorcelain)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	last := lines[2]
+	if last.number != 3 || last.author != "Grace Hopper" || last.code != "func main() {}" || last.time.Unix() != 1700086400 {
+		t.Fatalf("last line = %+v", last)
+	}
+
+	now := time.Unix(1700086400, 0).Add(3 * time.Hour)
+	msg, meta, _ := layoutCards(blameCards(lines, now))
+	if strings.Contains(msg, "L2 ") {
+		t.Errorf("blank lines should be skipped: %q", msg)
+	}
+	if !strings.Contains(msg, "L3  func main() {}") || !strings.Contains(meta, "by Grace Hopper 3 hours ago") {
+		t.Errorf("msg=%q meta=%q", msg, meta)
+	}
+	if strings.Index(msg, "L3") != strings.Index(meta, "by Grace") {
+		t.Errorf("cards are misaligned:\n%q\n%q", msg, meta)
+	}
+}
+
+func TestRelativeAge(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	for d, want := range map[time.Duration]string{
+		10 * time.Second:     "just now",
+		time.Minute:          "1 minute ago",
+		5 * time.Hour:        "5 hours ago",
+		3 * 24 * time.Hour:   "3 days ago",
+		21 * 24 * time.Hour:  "3 weeks ago",
+		90 * 24 * time.Hour:  "3 months ago",
+		800 * 24 * time.Hour: "2 years ago",
+	} {
+		if got := relativeAge(now.Add(-d), now); got != want {
+			t.Errorf("relativeAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// --burn-diff scrolls the lines a diff added and removed slowly up through
+// the lower third of the screen, where the flames eat them.
+
+// heatSource is implemented by visualizations that can say how hot a cell
+// is, so overlays can react to the flames.
+type heatSource interface {
+	Heat(x, y int) float64
+}
+
+// Heat levels at which burning text starts to waver and is consumed.
+const (
+	burnDistortHeat = 4
+	burnConsumeHeat = 12
+)
+
+// burnFramesPerRow is how many frames the text takes to rise one row.
+const burnFramesPerRow = 12
+
+var (
+	burnAddedStyle   = tcell.StyleDefault.Foreground(tcell.ColorGreen).Dim(true)
+	burnRemovedStyle = tcell.StyleDefault.Foreground(tcell.ColorRed).Dim(true)
+)
+
+// diffLine is an added or removed line of a diff.
+type diffLine struct {
+	text  []rune
+	added bool
+}
+
+// burningDiff is the --burn-diff text layer.
+type burningDiff struct {
+	lines []diffLine
+	frame int
+}
+
+// loadBurnDiff returns the uncommitted changes in dir or, if there are
+// none, the most recent commit's.
+func loadBurnDiff(dir string) []diffLine {
+	for _, args := range [][]string{{"diff", "HEAD"}, {"show", "--format=", "HEAD"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		if lines := parseDiffLines(string(out)); len(lines) > 0 {
+			return lines
+		}
+	}
+	return nil
+}
+
+// parseDiffLines picks the added and removed lines out of a unified diff.
+// Each hunk header says how many lines the hunk spans, which tells its
+// content apart from the next file's ---/+++ headers even when a removed
+// line itself starts with "--" or an added one with "++".
+func parseDiffLines(diff string) []diffLine {
+	var out []diffLine
+	var oldLeft, newLeft int
+	for _, line := range strings.Split(diff, "\n") {
+		if oldLeft <= 0 && newLeft <= 0 {
+			// Between hunks: file headers, or the next hunk's header.
+			if strings.HasPrefix(line, "@@") {
+				oldLeft, newLeft = parseHunkHeader(line)
+			}
+			continue
+		}
+		switch {
+		case line == "" || line[0] == ' ':
+			oldLeft--
+			newLeft--
+			continue
+		case line[0] == '-':
+			oldLeft--
+		case line[0] == '+':
+			newLeft--
+		default:
+			// "\ No newline at end of file".
+			continue
+		}
+		text := strings.ReplaceAll(strings.TrimRight(line, " \t\r"), "\t", "    ")
+		out = append(out, diffLine{text: []rune(text), added: line[0] == '+'})
+	}
+	return out
+}
+
+// parseHunkHeader returns the old and new line counts of a hunk header
+// such as "@@ -1,3 +1,4 @@", or zeros if it can't be parsed.
+func parseHunkHeader(line string) (oldCount, newCount int) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	count := func(r string) int {
+		_, n, ok := strings.Cut(r[1:], ",")
+		if !ok {
+			return 1 // a count of one is left out
+		}
+		c, err := strconv.Atoi(n)
+		if err != nil {
+			return 0
+		}
+		return c
+	}
+	if !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0
+	}
+	return count(fields[1]), count(fields[2])
+}
+
+func (b *burningDiff) advance(frame int) {
+	b.frame = frame
+}
+
+// over returns vis with the diff drawn on top, for a canvas of the given
+// height whose bottom reservedRows rows belong to the ticker.
+func (b *burningDiff) over(vis Visualization, height, reservedRows int) Visualization {
+	bottom := height - reservedRows
+	return &burnView{Visualization: vis, burn: b, top: bottom - bottom/3, bottom: bottom}
+}
+
+// burnView draws a burningDiff over a visualization between rows top and
+// bottom.
+type burnView struct {
+	Visualization
+	burn        *burningDiff
+	top, bottom int
+}
+
+func (v *burnView) Cell(x, y int) (rune, tcell.Style) {
+	ch, style := v.Visualization.Cell(x, y)
+	if y < v.top || y >= v.bottom || len(v.burn.lines) == 0 {
+		return ch, style
+	}
+	// Line i enters at the bottom on row i and rises one row every
+	// burnFramesPerRow frames; the whole diff then starts over.
+	cycle := len(v.burn.lines) + v.bottom - v.top
+	risen := v.burn.frame / burnFramesPerRow % cycle
+	i := y - v.bottom + risen
+	if i < 0 || i >= len(v.burn.lines) {
+		return ch, style
+	}
+	heat := 0.0
+	if hs, ok := v.Visualization.(heatSource); ok {
+		heat = hs.Heat(x, y)
+	}
+	if heat > burnConsumeHeat {
+		return ch, style
+	}
+	line := v.burn.lines[i]
+	col := x - 2
+	if heat > burnDistortHeat {
+		// Shimmer sideways in the heat.
+		col += (x*7+y*13+v.burn.frame/3)%3 - 1
+	}
+	if col < 0 || col >= len(line.text) || line.text[col] == ' ' {
+		return ch, style
+	}
+	if line.added {
+		return line.text[col], burnAddedStyle
+	}
+	return line.text[col], burnRemovedStyle
+}
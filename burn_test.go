@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDiffLines(t *testing.T) {
+	diff := `diff --git a/x.go b/x.go
+--- a/x.go
++++ b/x.go
+@@ -1,3 +1,3 @@
+ package x
+-	old()
++	new()
+`
+	lines := parseDiffLines(diff)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if string(lines[0].text) != "-    old()" || lines[0].added {
+		t.Errorf("first line = %q added=%v", string(lines[0].text), lines[0].added)
+	}
+	if string(lines[1].text) != "+    new()" || !lines[1].added {
+		t.Errorf("second line = %q added=%v", string(lines[1].text), lines[1].added)
+	}
+}
+
+func TestParseDiffLines_DashedContent(t *testing.T) {
+	// Removed SQL comments and a YAML separator, and an added "++" line,
+	// followed by a second file whose headers must still be skipped.
+	diff := `diff --git a/q.sql b/q.sql
+--- a/q.sql
++++ b/q.sql
+@@ -1,3 +1,2 @@
+-- old comment
+--- section
++++counter;
+ SELECT 1;
+\ No newline at end of file
+diff --git a/c.yml b/c.yml
+--- a/c.yml
++++ b/c.yml
+@@ -1 +1,2 @@
+ a: 1
++---
+`
+	var got []string
+	for _, l := range parseDiffLines(diff) {
+		got = append(got, string(l.text))
+	}
+	want := []string{"-- old comment", "--- section", "+++counter;", "+---"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// heated is a solid visualization with a uniform heat.
+type heated struct {
+	solid
+	heat float64
+}
+
+func (h *heated) Heat(x, y int) float64 { return h.heat }
+
+func TestBurnView(t *testing.T) {
+	b := &burningDiff{lines: []diffLine{{text: []rune("+abc"), added: true}}}
+	vis := &heated{solid: solid{ch: '#'}}
+	// A 12-row canvas with no ticker: the text rises through rows 8-11.
+	b.advance(burnFramesPerRow * 2)
+	view := b.over(vis, 12, 0)
+	if ch, style := view.Cell(2, 10); ch != '+' || style != burnAddedStyle {
+		t.Fatalf("cold cell = %q, want the diff text", ch)
+	}
+	if ch, _ := view.Cell(2, 5); ch != '#' {
+		t.Fatalf("above the lower third = %q, want the fire", ch)
+	}
+	vis.heat = burnConsumeHeat + 1
+	if ch, _ := view.Cell(2, 10); ch != '#' {
+		t.Fatalf("hot cell = %q, want the flames to consume the text", ch)
+	}
+}
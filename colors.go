@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// colorMode is how many colors the terminal can show.
+type colorMode int
+
+const (
+	colors16 colorMode = iota
+	colors256
+	colorsTrue
+)
+
+func (m colorMode) String() string {
+	switch m {
+	case colorsTrue:
+		return "truecolor"
+	case colors256:
+		return "256"
+	default:
+		return "16"
+	}
+}
+
+// parseColorMode parses the config's color setting. "auto" (or "") means
+// probe the terminal and returns ok=false.
+func parseColorMode(s string) (mode colorMode, ok bool, err error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return 0, false, nil
+	case "truecolor", "24bit":
+		return colorsTrue, true, nil
+	case "256":
+		return colors256, true, nil
+	case "16":
+		return colors16, true, nil
+	}
+	return 0, false, fmt.Errorf("color: want auto, truecolor, 256 or 16, got %q", s)
+}
+
+// forceColorMode makes tcell use (or not use) 24-bit color regardless of
+// what the terminal advertises. It must be called before creating the
+// screen.
+func forceColorMode(m colorMode) {
+	if m == colorsTrue {
+		os.Setenv("TCELL_TRUECOLOR", "enable")
+	} else {
+		os.Setenv("TCELL_TRUECOLOR", "disable")
+	}
+}
+
+// screenColorMode reports what an initialized screen supports.
+func screenColorMode(s tcell.Screen) colorMode {
+	switch n := s.Colors(); {
+	case n >= 1<<24:
+		return colorsTrue
+	case n >= 256:
+		return colors256
+	default:
+		return colors16
+	}
+}
+
+// detectColorMode probes the terminal from $TERM and $COLORTERM without
+// taking over the screen, following the same rules as tcell.
+func detectColorMode(getenv func(string) string) (colorMode, string) {
+	switch ct := getenv("COLORTERM"); ct {
+	case "truecolor", "24bit", "24-bit":
+		return colorsTrue, "COLORTERM=" + ct
+	}
+	if getenv("WT_SESSION") != "" {
+		// Windows Terminal supports 24-bit color but sets neither variable.
+		return colorsTrue, "Windows Terminal"
+	}
+	term := getenv("TERM")
+	ti, err := terminfo.LookupTerminfo(term)
+	switch {
+	case err != nil && strings.Contains(term, "256color"):
+		return colors256, "TERM=" + term
+	case err != nil:
+		return colors16, "TERM=" + term + " (unknown terminal)"
+	case ti.TrueColor || ti.SetFgRGB != "":
+		return colorsTrue, "TERM=" + term
+	case ti.Colors >= 256:
+		return colors256, "TERM=" + term
+	}
+	return colors16, "TERM=" + term
+}
+
+// bayer4 is a 4×4 ordered-dither threshold matrix.
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// Heats at which each of styles[1..4] is shown undiluted; in between,
+// neighbouring styles are blended (truecolor) or dithered (otherwise).
+// They sit in the middle of the classic bands (<=4, <=9, <=15, >15).
+var styleAnchors = [4]float64{2, 6.5, 12, 18}
+
+// gradientSteps is how many blended styles sit between two anchors.
+const gradientSteps = 8
+
+// forColors returns p prepared for a terminal with the given color
+// support: truecolor gets a precomputed smooth gradient between the
+// palette's styles, others dither between them, with 16-color terminals
+// snapped to the standard ANSI colors.
+func (p palette) forColors(m colorMode) palette {
+	p.gradient = nil
+	p.dither = m != colorsTrue
+	if m == colors16 {
+		ansi := make([]tcell.Color, 16)
+		for i := range ansi {
+			ansi[i] = tcell.PaletteColor(i)
+		}
+		styles := make([]tcell.Style, len(p.styles))
+		for i, st := range p.styles {
+			fg, _, _ := st.Decompose()
+			styles[i] = st.Foreground(tcell.FindColor(fg, ansi))
+		}
+		p.styles = styles
+	}
+	if m == colorsTrue {
+		for band := 1; band < 4; band++ {
+			for step := 0; step < gradientSteps; step++ {
+				p.gradient = append(p.gradient, blendStyles(p.styles[band], p.styles[band+1], float64(step)/gradientSteps))
+			}
+		}
+		p.gradient = append(p.gradient, p.styles[4])
+	}
+	return p
+}
+
+// blendStyles mixes a's foreground toward b's by t, keeping the attributes
+// of whichever is nearer. Colors without an RGB value (such as the
+// terminal's default) can't be mixed and switch over halfway.
+func blendStyles(a, b tcell.Style, t float64) tcell.Style {
+	switch {
+	case t <= 0:
+		return a
+	case t >= 1:
+		return b
+	}
+	base := a
+	if t >= 0.5 {
+		base = b
+	}
+	afg, _, _ := a.Decompose()
+	bfg, _, _ := b.Decompose()
+	ar, ag, ab := afg.RGB()
+	br, bg, bb := bfg.RGB()
+	if ar < 0 || br < 0 {
+		return base
+	}
+	mix := func(x, y int32) int32 { return x + int32(math.Round(float64(y-x)*t)) }
+	return base.Foreground(tcell.NewRGBColor(mix(ar, br), mix(ag, bg), mix(ab, bb)))
+}
+
+// bandPosition maps heat v onto [0,3]: 0 is styles[1], 3 is styles[4].
+func bandPosition(v float64) float64 {
+	if v <= styleAnchors[0] {
+		return 0
+	}
+	for i := 1; i < len(styleAnchors); i++ {
+		if v <= styleAnchors[i] {
+			lo, hi := styleAnchors[i-1], styleAnchors[i]
+			return float64(i-1) + (v-lo)/(hi-lo)
+		}
+	}
+	return 3
+}
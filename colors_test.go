@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDetectColorMode(t *testing.T) {
+	for _, tc := range []struct {
+		colorterm, term string
+		want            colorMode
+	}{
+		{"truecolor", "xterm", colorsTrue},
+		{"24bit", "", colorsTrue},
+		{"", "xterm-256color", colors256},
+		{"", "xterm", colors16},
+		{"", "no-such-terminal", colors16},
+	} {
+		env := map[string]string{"COLORTERM": tc.colorterm, "TERM": tc.term}
+		if got, _ := detectColorMode(func(k string) string { return env[k] }); got != tc.want {
+			t.Errorf("COLORTERM=%q TERM=%q: got %v, want %v", tc.colorterm, tc.term, got, tc.want)
+		}
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	if _, ok, err := parseColorMode("auto"); ok || err != nil {
+		t.Fatalf("auto: ok=%v err=%v", ok, err)
+	}
+	if m, ok, err := parseColorMode("256"); !ok || err != nil || m != colors256 {
+		t.Fatalf("256: %v %v %v", m, ok, err)
+	}
+	if _, _, err := parseColorMode("lots"); err == nil {
+		t.Fatalf("expected an error for an unknown mode")
+	}
+}
+
+func TestPaletteGradient(t *testing.T) {
+	pal := firePalette().forColors(colorsTrue)
+	if _, style := pal.glyph(0, 0, 0); style != pal.styles[1] {
+		t.Fatalf("coolest heat should use the first style")
+	}
+	if _, style := pal.glyph(70, 0, 0); style != pal.styles[4] {
+		t.Fatalf("hottest heat should use the last style")
+	}
+	// Between two anchors the color is a blend, not either endpoint.
+	_, style := pal.glyph(9.25, 0, 0)
+	if style == pal.styles[2] || style == pal.styles[3] {
+		t.Fatalf("expected a blended style between bands")
+	}
+}
+
+func TestPaletteDither(t *testing.T) {
+	pal := firePalette().forColors(colors256)
+	// Halfway between two anchors, half of each 4×4 block uses each style.
+	counts := map[tcell.Style]int{}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			_, style := pal.glyph(9.25, x, y)
+			counts[style]++
+		}
+	}
+	if counts[pal.styles[2]] != 8 || counts[pal.styles[3]] != 8 {
+		t.Fatalf("dither counts = %v, want 8 of each neighbouring style", counts)
+	}
+}
+
+func TestPalette16Colors(t *testing.T) {
+	pal := contribsPalette().forColors(colors16)
+	for i, st := range pal.styles {
+		fg, _, _ := st.Decompose()
+		if fg&tcell.ColorIsRGB != 0 || fg-tcell.ColorValid >= 16 {
+			t.Errorf("style %d uses %v, want one of the 16 ANSI colors", i, fg)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config holds the parsed config file as section -> key -> value. Keys
+// before the first [section] header live in the "" section.
+//
+// The format is a small INI/TOML subset:
+//
+//	# comment
+//	theme = "fire"
+//
+//	[keys]
+//	exit = ["q", "esc"]
+type config map[string]map[string]string
+
+// configPath returns $YULE_LOG_CONFIG if set, otherwise
+// <user config dir>/gh-yule-log/config.
+func configPath() (string, error) {
+	if p := os.Getenv("YULE_LOG_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-yule-log", "config"), nil
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig() (config, error) {
+	path, err := configPath()
+	if err != nil {
+		return config{}, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg, err := parseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfigValue sets key in section of the config file to value (written
+// verbatim, so strings should already be quoted), creating the file if
+// needed and leaving everything else untouched.
+func saveConfigValue(section, key, value string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updateConfigText(string(data), section, key, value)), 0o644)
+}
+
+// updateConfigText returns text with key in section set to value. An
+// existing entry is replaced in place; otherwise the entry is added to the
+// end of its section, creating the section if necessary.
+func updateConfigText(text, section, key, value string) string {
+	entry := key + " = " + value
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if text == "" {
+		lines = nil
+	}
+
+	current := ""
+	insertAt := -1 // after the last entry of the target section
+	firstHeader := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			if firstHeader < 0 {
+				firstHeader = i
+			}
+			current = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if current == section {
+				insertAt = i + 1
+			}
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if k, _, ok := strings.Cut(trimmed, "="); ok && !strings.HasPrefix(trimmed, "#") {
+			if strings.TrimSpace(k) == key {
+				lines[i] = entry
+				return strings.Join(lines, "\n") + "\n"
+			}
+			insertAt = i + 1
+		}
+	}
+
+	if section == "" && insertAt < 0 {
+		// No top-level entries yet: they go before the first section.
+		insertAt = len(lines)
+		if firstHeader >= 0 {
+			insertAt = firstHeader
+			entry += "\n"
+		}
+	}
+	if insertAt < 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+section+"]", entry)
+		return strings.Join(lines, "\n") + "\n"
+	}
+	lines = append(lines[:insertAt], append([]string{entry}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func parseConfig(r io.Reader) (config, error) {
+	cfg := config{}
+	section := ""
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section header %q", n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: missing key", n)
+		}
+		cfg.set(section, key, unquote(strings.TrimSpace(value)))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// get returns the value of key in section.
+func (c config) get(section, key string) (string, bool) {
+	v, ok := c[section][key]
+	return v, ok
+}
+
+// set stores value under key in section, as if it had been read from the
+// file.
+func (c config) set(section, key, value string) {
+	if c[section] == nil {
+		c[section] = map[string]string{}
+	}
+	c[section][key] = value
+}
+
+// configList splits a value such as `["q", "esc"]` or `q, esc` into its
+// elements.
+func configList(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = unquote(strings.TrimSpace(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
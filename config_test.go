@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := parseConfig(strings.NewReader(`
+# top-level settings
+theme = "fire"
+
+[keys]
+exit = ["q", "esc"]
+intensity-up = k
+`))
+	if err != nil {
+		t.Fatalf("parseConfig: %v", err)
+	}
+	if v, _ := cfg.get("", "theme"); v != "fire" {
+		t.Fatalf("theme = %q, want fire", v)
+	}
+	v, ok := cfg.get("keys", "exit")
+	if !ok {
+		t.Fatalf("expected [keys] exit")
+	}
+	if got, want := configList(v), []string{"q", "esc"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("configList(%q) = %q, want %q", v, got, want)
+	}
+	if _, ok := cfg.get("keys", "missing"); ok {
+		t.Fatalf("expected missing key to be absent")
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	for _, in := range []string{"[keys", "no equals sign", " = value"} {
+		if _, err := parseConfig(strings.NewReader(in)); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestUpdateConfigText(t *testing.T) {
+	for _, tc := range []struct {
+		name, in, section, key, value, want string
+	}{
+		{
+			name: "empty file", in: "", section: "", key: "intensity", value: "70",
+			want: "intensity = 70\n",
+		},
+		{
+			name: "replace top-level", in: "# mine\nintensity = 40\n\n[keys]\nexit = q\n",
+			section: "", key: "intensity", value: "70",
+			want: "# mine\nintensity = 70\n\n[keys]\nexit = q\n",
+		},
+		{
+			name: "add top-level before sections", in: "theme = fire\n\n[keys]\nexit = q\n",
+			section: "", key: "intensity", value: "70",
+			want: "theme = fire\nintensity = 70\n\n[keys]\nexit = q\n",
+		},
+		{
+			name: "add top-level to file with only sections", in: "# mine\n[keys]\nexit = q\n",
+			section: "", key: "intensity", value: "70",
+			want: "# mine\nintensity = 70\n\n[keys]\nexit = q\n",
+		},
+		{
+			name: "add to existing section", in: "[keys]\nexit = q\n\n[other]\na = b\n",
+			section: "keys", key: "pause", value: "p",
+			want: "[keys]\nexit = q\npause = p\n\n[other]\na = b\n",
+		},
+		{
+			name: "new section", in: "intensity = 70\n",
+			section: "keys", key: "exit", value: "q",
+			want: "intensity = 70\n\n[keys]\nexit = q\n",
+		},
+	} {
+		if got := updateConfigText(tc.in, tc.section, tc.key, tc.value); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The running screensaver listens on a per-user Unix socket so that
+// scripts and tmux bindings can drive it with `gh yule-log ctl`. Each
+// connection carries one command line, "verb args...", and gets back
+// "ok" or "error: ..." once the main loop has applied it.
+
+// ctlTimeout bounds how long either side waits on the other.
+const ctlTimeout = 3 * time.Second
+
+// ctlReloadTimeout bounds how long reload-ticker may take, since it runs
+// git, which can be slow on big repositories.
+const ctlReloadTimeout = 30 * time.Second
+
+// ctlUsage lists the verbs ctl accepts.
+const ctlUsage = `usage: gh yule-log ctl <command>
+
+Commands:
+  dismiss               quit the screensaver
+  set-theme NAME        switch theme
+  set-intensity N       set the flame height (10-85)
+  show-message TEXT     show TEXT above the flames (empty hides it)
+  reload-ticker         re-read the ticker's commits, releases or blame`
+
+// ctlCommand is a parsed control command.
+type ctlCommand struct {
+	verb string
+	arg  string
+	// atFrame is set by the --all-panes orchestrator so every renderer
+	// applies the command on the same frame. Zero means straight away.
+	atFrame int
+}
+
+// parseCtlCommand parses and checks a command line. The orchestrator
+// prefixes the lines it passes on to renderers with "@FRAME ".
+func parseCtlCommand(line string) (ctlCommand, error) {
+	line = strings.TrimSpace(line)
+	var atFrame int
+	if rest, ok := strings.CutPrefix(line, "@"); ok {
+		n, cmdLine, _ := strings.Cut(rest, " ")
+		f, err := strconv.Atoi(n)
+		if err != nil || f < 0 {
+			return ctlCommand{}, fmt.Errorf("bad frame %q", n)
+		}
+		atFrame, line = f, strings.TrimSpace(cmdLine)
+	}
+	verb, arg, _ := strings.Cut(line, " ")
+	cmd := ctlCommand{verb: verb, arg: strings.TrimSpace(arg), atFrame: atFrame}
+	switch verb {
+	case "dismiss", "reload-ticker":
+		if cmd.arg != "" {
+			return cmd, fmt.Errorf("%s takes no arguments", verb)
+		}
+	case "set-theme":
+		if cmd.arg == "" {
+			return cmd, errors.New("set-theme needs a theme name")
+		}
+	case "set-intensity":
+		n, err := strconv.Atoi(cmd.arg)
+		if err != nil {
+			return cmd, fmt.Errorf("set-intensity needs a number, got %q", cmd.arg)
+		}
+		if n < minHeat || n > maxHeat {
+			return cmd, fmt.Errorf("set-intensity must be between %d and %d, got %d", minHeat, maxHeat, n)
+		}
+	case "show-message":
+	case "":
+		return cmd, errors.New("no command")
+	default:
+		return cmd, fmt.Errorf("unknown command %q", verb)
+	}
+	return cmd, nil
+}
+
+// String returns c as a line parseCtlCommand reads back.
+func (c ctlCommand) String() string {
+	line := c.verb
+	if c.arg != "" {
+		line += " " + c.arg
+	}
+	if c.atFrame > 0 {
+		line = "@" + strconv.Itoa(c.atFrame) + " " + line
+	}
+	return line
+}
+
+// replyTimeout is how long the screensaver may take to carry out c once it
+// has it.
+func (c ctlCommand) replyTimeout() time.Duration {
+	if c.verb == "reload-ticker" {
+		return ctlReloadTimeout
+	}
+	return ctlTimeout
+}
+
+// tickerEvent delivers cards loaded for reload-ticker to the main loop,
+// which applies them and sends the outcome on reply.
+type tickerEvent struct {
+	tcell.EventTime
+	cards []tickerCard
+	err   error
+	reply chan error
+}
+
+// ctlEvent delivers a control command to the main loop, which sends the
+// outcome on reply.
+type ctlEvent struct {
+	tcell.EventTime
+	cmd   ctlCommand
+	reply chan error
+}
+
+// controlSocketPath returns where the screensaver listens: $YULE_LOG_SOCKET,
+// or a socket in $XDG_RUNTIME_DIR or the user's cache directory.
+func controlSocketPath() (string, error) {
+	if p := os.Getenv("YULE_LOG_SOCKET"); p != "" {
+		return p, nil
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gh-yule-log.sock"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-yule-log", "ctl.sock"), nil
+}
+
+// listenControl starts accepting control commands, forwarding each to
+// events. Only one screensaver can listen at a time; a second one gets
+// an error. Closing the returned listener stops it and removes the socket.
+func listenControl(events chan<- tcell.Event) (net.Listener, error) {
+	path, err := controlSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	if conn, err := net.DialTimeout("unix", path, ctlTimeout); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another screensaver is listening on %s", path)
+	}
+	// Nobody answered, so a socket left behind is stale. Anything else
+	// at the path, such as a mistyped $YULE_LOG_SOCKET, is left alone.
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and isn't a socket", path)
+		}
+		os.Remove(path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	ln, err := listenUnix(path)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveControl(conn, events)
+		}
+	}()
+	return ln, nil
+}
+
+// serveControl handles one control connection.
+func serveControl(conn net.Conn, events chan<- tcell.Event) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * ctlTimeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+	err = func() error {
+		cmd, err := parseCtlCommand(line)
+		if err != nil {
+			return err
+		}
+		conn.SetDeadline(time.Now().Add(ctlTimeout + cmd.replyTimeout()))
+		ev := &ctlEvent{cmd: cmd, reply: make(chan error, 1)}
+		ev.SetEventNow()
+		select {
+		case events <- ev:
+		case <-time.After(ctlTimeout):
+			return errors.New("screensaver is not responding")
+		}
+		select {
+		case err := <-ev.reply:
+			return err
+		case <-time.After(cmd.replyTimeout()):
+			return errors.New("screensaver is not responding")
+		}
+	}()
+	if err != nil {
+		fmt.Fprintf(conn, "error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(conn, "ok")
+}
+
+// runCtl implements `gh yule-log ctl`, sending a command to the running
+// screensaver.
+func runCtl(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() { fmt.Fprintln(w, ctlUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cmd, err := parseCtlCommand(strings.Join(fs.Args(), " "))
+	if err != nil {
+		fs.Usage()
+		return err
+	}
+	path, err := controlSocketPath()
+	if err != nil {
+		return err
+	}
+	return sendControl(path, cmd)
+}
+
+// sendControl sends cmd to the screensaver listening on path and returns
+// the error it replies with, if any.
+func sendControl(path string, cmd ctlCommand) error {
+	conn, err := net.DialTimeout("unix", path, ctlTimeout)
+	if err != nil {
+		return fmt.Errorf("no screensaver is running (%v)", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2*ctlTimeout + cmd.replyTimeout()))
+	if _, err := fmt.Fprintln(conn, cmd); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading reply: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if msg, ok := strings.CutPrefix(reply, "error: "); ok {
+		return errors.New(msg)
+	}
+	return nil
+}
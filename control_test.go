@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseCtlCommand(t *testing.T) {
+	for _, tt := range []struct {
+		line    string
+		want    ctlCommand
+		wantErr bool
+	}{
+		{line: "dismiss\n", want: ctlCommand{verb: "dismiss"}},
+		{line: "set-theme snow", want: ctlCommand{verb: "set-theme", arg: "snow"}},
+		{line: "set-intensity 80", want: ctlCommand{verb: "set-intensity", arg: "80"}},
+		{line: "set-intensity 120", wantErr: true},
+		{line: "set-intensity 5", wantErr: true},
+		{line: "show-message deploy done 🎉", want: ctlCommand{verb: "show-message", arg: "deploy done 🎉"}},
+		{line: "show-message", want: ctlCommand{verb: "show-message"}},
+		{line: "reload-ticker", want: ctlCommand{verb: "reload-ticker"}},
+		{line: "", wantErr: true},
+		{line: "set-theme", wantErr: true},
+		{line: "set-intensity hot", wantErr: true},
+		{line: "dismiss now", wantErr: true},
+		{line: "explode", wantErr: true},
+		{line: "@120 set-theme snow", want: ctlCommand{verb: "set-theme", arg: "snow", atFrame: 120}},
+		{line: "@soon set-theme snow", wantErr: true},
+	} {
+		got, err := parseCtlCommand(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCtlCommand(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseCtlCommand(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+		if again, err := parseCtlCommand(got.String()); !tt.wantErr && (err != nil || again != got) {
+			t.Errorf("%+v doesn't survive String: %+v, %v", got, again, err)
+		}
+	}
+}
+
+func TestControlSocket(t *testing.T) {
+	t.Setenv("YULE_LOG_SOCKET", filepath.Join(t.TempDir(), "ctl.sock"))
+	events := make(chan tcell.Event)
+	ln, err := listenControl(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if _, err := listenControl(events); err == nil {
+		t.Error("second listener started alongside the first")
+	}
+
+	// Stand in for the main loop: accept everything but unknown themes.
+	got := make(chan ctlCommand, 1)
+	go func() {
+		for ev := range events {
+			ev := ev.(*ctlEvent)
+			got <- ev.cmd
+			if ev.cmd.arg == "matrix" {
+				ev.reply <- errors.New("unknown theme")
+			} else {
+				ev.reply <- nil
+			}
+		}
+	}()
+	defer close(events)
+
+	if err := runCtl([]string{"show-message", "deploy", "done"}, io.Discard); err != nil {
+		t.Fatalf("show-message: %v", err)
+	}
+	if cmd := <-got; cmd != (ctlCommand{verb: "show-message", arg: "deploy done"}) {
+		t.Errorf("screensaver got %+v", cmd)
+	}
+	if err := runCtl([]string{"set-theme", "matrix"}, io.Discard); err == nil || err.Error() != "unknown theme" {
+		t.Errorf("set-theme matrix: err = %v, want the screensaver's error", err)
+	}
+	<-got
+}
+
+func TestRunCtl_NotRunning(t *testing.T) {
+	t.Setenv("YULE_LOG_SOCKET", filepath.Join(t.TempDir(), "ctl.sock"))
+	if err := runCtl([]string{"dismiss"}, io.Discard); err == nil {
+		t.Error("ctl succeeded with no screensaver running")
+	}
+}
+
+func TestListenControl_SocketSafety(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	t.Setenv("YULE_LOG_SOCKET", path)
+	if err := os.WriteFile(path, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listenControl(make(chan tcell.Event)); err == nil {
+		t.Fatal("listened over a regular file")
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "keep me" {
+		t.Fatalf("regular file was touched: %q, %v", b, err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	path = filepath.Join(t.TempDir(), "ctl.sock")
+	t.Setenv("YULE_LOG_SOCKET", path)
+	ln, err := listenControl(make(chan tcell.Event))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("socket mode = %v, want only the owner", perm)
+	}
+}
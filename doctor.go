@@ -0,0 +1,273 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// checkResult is one line of `yule-log doctor` output.
+type checkResult struct {
+	name   string
+	ok     bool
+	detail string
+	hint   string // how to fix it, shown when !ok
+	// warn marks a failing check that only limits an optional feature, so
+	// it doesn't make doctor fail.
+	warn bool
+}
+
+// doctorEnv is how the checks look at the outside world, so tests can fake
+// it.
+type doctorEnv struct {
+	goos     string
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+	// run runs a command in dir ("" for the current directory) and returns
+	// its combined output.
+	run func(dir, name string, args ...string) (string, error)
+	// loadConfig, configPath, gitDir and controlSocketPath stand in for
+	// the functions of the same names.
+	loadConfig        func() (config, error)
+	configPath        func() (string, error)
+	gitDir            func() string
+	controlSocketPath func() (string, error)
+	lstat             func(string) (os.FileInfo, error)
+}
+
+func systemDoctorEnv() doctorEnv {
+	return doctorEnv{
+		goos:     runtime.GOOS,
+		getenv:   os.Getenv,
+		lookPath: exec.LookPath,
+		run: func(dir, name string, args ...string) (string, error) {
+			cmd := exec.Command(name, args...)
+			cmd.Dir = dir
+			out, err := cmd.CombinedOutput()
+			return strings.TrimSpace(string(out)), err
+		},
+		loadConfig:        loadConfig,
+		configPath:        configPath,
+		gitDir:            gitDir,
+		controlSocketPath: controlSocketPath,
+		lstat:             os.Lstat,
+	}
+}
+
+// runDoctor implements `yule-log doctor`: check the environment and explain
+// how to fix anything that would stop a feature from working. It returns an
+// error if any check failed, so scripts can tell from the exit status.
+func runDoctor(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return reportDoctor(w, doctorChecks(systemDoctorEnv()))
+}
+
+// reportDoctor prints results and returns an error if any check failed.
+// Warnings are printed with their hints but don't count.
+func reportDoctor(w io.Writer, results []checkResult) error {
+	problems := 0
+	for _, r := range results {
+		mark := "✓"
+		switch {
+		case !r.ok && r.warn:
+			mark = "!"
+		case !r.ok:
+			mark = "✗"
+			problems++
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", mark, r.name, r.detail)
+		if !r.ok && r.hint != "" {
+			fmt.Fprintf(w, "    → %s\n", r.hint)
+		}
+	}
+	switch problems {
+	case 0:
+		fmt.Fprintln(w, "\nEverything looks good. Enjoy the fire!")
+		return nil
+	case 1:
+		return errors.New("1 check failed")
+	default:
+		return fmt.Errorf("%d checks failed", problems)
+	}
+}
+
+func doctorChecks(env doctorEnv) []checkResult {
+	var results []checkResult
+
+	// tmux, needed for --all-panes and the status widget. There's no tmux
+	// on Windows, where the fire simply runs fullscreen in the console.
+	if env.goos == "windows" {
+		results = append(results, checkResult{name: "tmux", ok: true, detail: "not used on Windows (--all-panes is unavailable)"})
+	} else {
+		results = append(results, tmuxChecks(env)...)
+	}
+
+	// Colors.
+	mode, why := detectColorMode(env.getenv)
+	if cfg, err := env.loadConfig(); err == nil {
+		v, _ := cfg.get("", "color")
+		if m, ok, err := parseColorMode(v); err == nil && ok {
+			mode, why = m, "color = "+v+" in config"
+		}
+	}
+	if mode == colorsTrue {
+		results = append(results, checkResult{name: "colors", ok: true, detail: "truecolor (" + why + ")"})
+	} else {
+		results = append(results, checkResult{
+			name: "colors", detail: mode.String() + " colors, flames are dithered (" + why + ")", warn: true,
+			hint: "if your terminal supports 24-bit color, export COLORTERM=truecolor or set color = truecolor in the config",
+		})
+	}
+
+	// git and the repository the ticker reads.
+	if _, err := env.lookPath("git"); err != nil {
+		results = append(results, checkResult{
+			name: "git", detail: "not installed",
+			hint: "install git to see your commits in the ticker",
+		})
+	} else {
+		version, _ := env.run("", "git", "--version")
+		results = append(results, checkResult{name: "git", ok: true, detail: version})
+		dir := env.gitDir()
+		if top, err := env.run(dir, "git", "rev-parse", "--show-toplevel"); err == nil {
+			results = append(results, checkResult{name: "repository", ok: true, detail: top})
+		} else {
+			results = append(results, checkResult{
+				name: "repository", detail: "no git repository at " + dir,
+				hint: "run gh yule-log from inside a repository to get the commit ticker",
+			})
+		}
+	}
+
+	// gh, which runs the extension.
+	if _, err := env.lookPath("gh"); err != nil {
+		results = append(results, checkResult{
+			name: "gh", detail: "not installed",
+			hint: "install the GitHub CLI from https://cli.github.com/",
+		})
+	} else if _, err := env.run("", "gh", "auth", "status"); err != nil {
+		results = append(results, checkResult{
+			name: "gh auth", detail: "not logged in", warn: true,
+			hint: "run gh auth login",
+		})
+	} else {
+		results = append(results, checkResult{name: "gh auth", ok: true, detail: "logged in"})
+	}
+
+	// Config file.
+	path, _ := env.configPath()
+	if cfg, err := env.loadConfig(); err != nil {
+		results = append(results, checkResult{name: "config", detail: err.Error(), hint: "fix or remove " + path})
+	} else if _, err := newKeymap(cfg); err != nil {
+		results = append(results, checkResult{name: "config", detail: err.Error(), hint: "fix the [keys] section of " + path})
+	} else if _, err := seasonRules(cfg); err != nil {
+		results = append(results, checkResult{name: "config", detail: err.Error(), hint: "fix the [auto-theme] section of " + path})
+	} else {
+		results = append(results, checkResult{name: "config", ok: true, detail: path})
+	}
+
+	// Audio for --sound.
+	player := ""
+	for _, argv := range audioPlayers {
+		if _, err := env.lookPath(argv[0]); err == nil {
+			player = argv[0]
+			break
+		}
+	}
+	if fallback := fallbackAudioPlayer(env.goos); player == "" && fallback != "" {
+		if _, err := env.lookPath(fallback); err == nil {
+			player = fallback
+		}
+	}
+	if player != "" {
+		results = append(results, checkResult{name: "audio player", ok: true, detail: player})
+	} else {
+		results = append(results, checkResult{
+			name: "audio player", detail: "none found", warn: true,
+			hint: "install PulseAudio (paplay), PipeWire (pw-cat), ALSA (aplay) or sox (play) to use --sound",
+		})
+	}
+
+	results = append(results, controlSocketCheck(env))
+
+	return results
+}
+
+// controlSocketCheck makes sure only the user can reach the socket `ctl`
+// talks to, if a screensaver has one open.
+func controlSocketCheck(env doctorEnv) checkResult {
+	path, err := env.controlSocketPath()
+	if err != nil {
+		return checkResult{name: "control socket", detail: err.Error(), hint: "set YULE_LOG_SOCKET to use gh yule-log ctl"}
+	}
+	fi, err := env.lstat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return checkResult{name: "control socket", ok: true, detail: "no screensaver running (" + path + ")"}
+	case err != nil:
+		return checkResult{name: "control socket", detail: err.Error(), hint: "check the permissions of " + filepath.Dir(path)}
+	case fi.Mode()&os.ModeSocket == 0:
+		return checkResult{name: "control socket", detail: path + " isn't a socket", hint: "point YULE_LOG_SOCKET somewhere else, or remove " + path}
+	case env.goos != "windows" && fi.Mode().Perm()&0o077 != 0:
+		return checkResult{
+			name: "control socket", detail: fmt.Sprintf("%s is %v, so others can use it", path, fi.Mode().Perm()),
+			hint: "run chmod 600 " + path + ", or restart the screensaver",
+		}
+	}
+	return checkResult{name: "control socket", ok: true, detail: path}
+}
+
+// tmuxChecks reports whether tmux is installed and we're running in it.
+func tmuxChecks(env doctorEnv) []checkResult {
+	var results []checkResult
+	if _, err := env.lookPath("tmux"); err != nil {
+		results = append(results, checkResult{
+			name: "tmux", detail: "not installed",
+			hint: "install tmux to use --all-panes and status-widget",
+		})
+	} else {
+		version, _ := env.run("", "tmux", "-V")
+		if tmuxOlderThan(version, minTmuxMajor, minTmuxMinor) {
+			results = append(results, checkResult{
+				name: "tmux", detail: version,
+				hint: fmt.Sprintf("upgrade to tmux %d.%d or later to use --all-panes", minTmuxMajor, minTmuxMinor),
+			})
+		} else {
+			results = append(results, checkResult{name: "tmux", ok: true, detail: version})
+		}
+	}
+	if env.getenv("TMUX") != "" {
+		results = append(results, checkResult{name: "inside tmux", ok: true, detail: "yes"})
+	} else {
+		results = append(results, checkResult{
+			name: "inside tmux", detail: "no", warn: true,
+			hint: "start a tmux session before using --all-panes",
+		})
+	}
+	return results
+}
+
+// The oldest tmux --all-panes works with: it sets pane options with
+// set-option -p.
+const minTmuxMajor, minTmuxMinor = 3, 0
+
+// tmuxOlderThan reports whether `tmux -V` output such as "tmux 3.3a" or
+// "tmux next-3.4" names a release before major.minor. Versions it can't
+// read, like "tmux master", are assumed new enough.
+func tmuxOlderThan(version string, major, minor int) bool {
+	v := strings.TrimPrefix(strings.TrimPrefix(version, "tmux "), "next-")
+	var gotMajor, gotMinor int
+	if n, _ := fmt.Sscanf(v, "%d.%d", &gotMajor, &gotMinor); n < 2 {
+		return false
+	}
+	return gotMajor < major || gotMajor == major && gotMinor < minor
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeDoctorEnv is a doctorEnv with an empty config and nothing else
+// installed or set.
+func fakeDoctorEnv(goos string) doctorEnv {
+	return doctorEnv{
+		goos:              goos,
+		getenv:            func(string) string { return "" },
+		lookPath:          func(string) (string, error) { return "", errors.New("not found") },
+		run:               func(string, string, ...string) (string, error) { return "", errors.New("not found") },
+		loadConfig:        func() (config, error) { return config{}, nil },
+		configPath:        func() (string, error) { return "/home/u/.config/yule-log/config.toml", nil },
+		gitDir:            func() string { return "/src/repo" },
+		controlSocketPath: func() (string, error) { return "/run/user/1000/gh-yule-log.sock", nil },
+		lstat:             func(string) (os.FileInfo, error) { return nil, os.ErrNotExist },
+	}
+}
+
+func TestDoctorChecks(t *testing.T) {
+	installed := map[string]bool{"tmux": true, "git": true, "gh": true}
+	env := fakeDoctorEnv("linux")
+	env.getenv = func(k string) string {
+		if k == "COLORTERM" {
+			return "truecolor"
+		}
+		return ""
+	}
+	env.lookPath = func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	env.run = func(dir, name string, args ...string) (string, error) {
+		switch name + " " + strings.Join(args, " ") {
+		case "tmux -V":
+			return "tmux 3.4", nil
+		case "git --version":
+			return "git version 2.43.0", nil
+		case "git rev-parse --show-toplevel":
+			return "/src/repo", nil
+		case "gh auth status":
+			return "", errors.New("exit status 1")
+		}
+		return "", errors.New("unexpected command")
+	}
+
+	results := map[string]checkResult{}
+	for _, r := range doctorChecks(env) {
+		results[r.name] = r
+	}
+	for name, wantOK := range map[string]bool{
+		"tmux":           true,
+		"inside tmux":    false,
+		"colors":         true,
+		"git":            true,
+		"repository":     true,
+		"gh auth":        false,
+		"config":         true,
+		"audio player":   false,
+		"control socket": true,
+	} {
+		r, ok := results[name]
+		if !ok {
+			t.Fatalf("missing check %q", name)
+		}
+		if r.ok != wantOK {
+			t.Errorf("%s: ok = %v, want %v (%s)", name, r.ok, wantOK, r.detail)
+		}
+		if !r.ok && r.hint == "" {
+			t.Errorf("%s: failing check has no hint", name)
+		}
+	}
+	if got := results["tmux"].detail; got != "tmux 3.4" {
+		t.Errorf("tmux detail = %q", got)
+	}
+
+	// Only optional features are missing, so doctor passes.
+	var out strings.Builder
+	if err := reportDoctor(&out, doctorChecks(env)); err != nil {
+		t.Errorf("reportDoctor = %v, want warnings only:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "! inside tmux: no") || !strings.Contains(out.String(), "run gh auth login") {
+		t.Errorf("warnings should still be shown with their hints:\n%s", out.String())
+	}
+
+	// A broken config is a real failure.
+	env.loadConfig = func() (config, error) { return nil, errors.New("line 3: bad value") }
+	if err := reportDoctor(&out, doctorChecks(env)); err == nil || err.Error() != "1 check failed" {
+		t.Errorf("reportDoctor with a broken config = %v, want 1 check failed", err)
+	}
+}
+
+// fakeFileInfo is an os.FileInfo with just a mode.
+type fakeFileInfo struct {
+	os.FileInfo
+	mode os.FileMode
+}
+
+func (f fakeFileInfo) Mode() os.FileMode { return f.mode }
+
+func TestControlSocketCheck(t *testing.T) {
+	env := fakeDoctorEnv("linux")
+	for _, tc := range []struct {
+		mode os.FileMode
+		ok   bool
+	}{
+		{os.ModeSocket | 0o600, true},
+		{os.ModeSocket | 0o666, false},
+		{0o644, false}, // a regular file
+	} {
+		env.lstat = func(string) (os.FileInfo, error) { return fakeFileInfo{mode: tc.mode}, nil }
+		if r := controlSocketCheck(env); r.ok != tc.ok || !r.ok && r.hint == "" {
+			t.Errorf("mode %v: ok = %v (%s), want %v", tc.mode, r.ok, r.detail, tc.ok)
+		}
+	}
+}
+
+func TestTmuxOlderThan(t *testing.T) {
+	for version, want := range map[string]bool{
+		"tmux 3.4":      false,
+		"tmux 3.0":      false,
+		"tmux 3.3a":     false,
+		"tmux next-3.5": false,
+		"tmux master":   false,
+		"tmux 2.9a":     true,
+		"tmux 1.8":      true,
+	} {
+		if got := tmuxOlderThan(version, 3, 0); got != want {
+			t.Errorf("tmuxOlderThan(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestDoctorChecks_Windows(t *testing.T) {
+	env := fakeDoctorEnv("windows")
+	env.getenv = func(k string) string { return map[string]string{"WT_SESSION": "1"}[k] }
+	env.lookPath = func(name string) (string, error) {
+		if name == "powershell" {
+			return `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, nil
+		}
+		return "", errors.New("not found")
+	}
+	for _, r := range doctorChecks(env) {
+		switch r.name {
+		case "audio player":
+			if !r.ok || r.detail != "powershell" {
+				t.Errorf("audio player = %v %q, want PowerShell", r.ok, r.detail)
+			}
+		case "inside tmux":
+			t.Errorf("tmux session check shouldn't run on Windows")
+		case "tmux", "colors":
+			if !r.ok {
+				t.Errorf("%s: ok = false on Windows (%s)", r.name, r.detail)
+			}
+		}
+	}
+}
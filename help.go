@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// helpLines lists the controls for the help overlay, generated from the
+// keymap so it matches any configured bindings, followed by the flags the
+// screensaver was started with. In --playground, keys with no binding of
+// their own are typing, so the any-key fallback is left out and a
+// playground section explains the difference.
+func helpLines(keys *keymap, flags *flag.FlagSet, playground bool) []string {
+	type row struct{ keys, desc string }
+	var rows []row
+	width := 0
+	for _, a := range actions {
+		var display []string
+		for _, name := range keys.keysFor(a) {
+			if name == anyKey {
+				if playground {
+					continue
+				}
+				name = "any other key"
+			}
+			display = append(display, name)
+		}
+		if playground && a == actionExit && !keys.exitBound() {
+			display = append(display, "esc")
+		}
+		if len(display) == 0 {
+			continue
+		}
+		r := row{keys: strings.Join(display, ", "), desc: actionDescriptions[a]}
+		if n := len([]rune(r.keys)); n > width {
+			width = n
+		}
+		rows = append(rows, r)
+	}
+
+	lines := []string{"Controls", ""}
+	for _, r := range rows {
+		lines = append(lines, padRight(r.keys, width+2)+r.desc)
+	}
+	if playground {
+		lines = append(lines, "", "Playground", "",
+			padRight("any other key", width+2)+"counts towards your typing speed")
+	}
+	var set []string
+	flags.Visit(func(f *flag.Flag) {
+		if f.Value.String() == "true" {
+			set = append(set, "--"+f.Name)
+		} else {
+			set = append(set, fmt.Sprintf("--%s=%s", f.Name, f.Value))
+		}
+	})
+	if len(set) > 0 {
+		lines = append(lines, "", "Flags: "+strings.Join(set, " "))
+	}
+	return lines
+}
+
+// drawHelp draws lines in a box centered on the screen.
+func drawHelp(s tcell.Screen, cache *frameCache, width, height int, lines []string) {
+	boxW, boxH := boxSize(lines)
+	drawBox(s, cache, (width-boxW)/2, (height-boxH)/2, lines, true)
+}
+
+// boxSize returns the outer size of a box drawn around lines.
+func boxSize(lines []string) (w, h int) {
+	innerW := 0
+	for _, l := range lines {
+		if n := len([]rune(l)); n > innerW {
+			innerW = n
+		}
+	}
+	return innerW + 4, len(lines) + 2
+}
+
+// drawBox draws lines in a bordered box with its top-left corner at
+// (x0, y0). With titled set, the first line is bold.
+func drawBox(s tcell.Screen, cache *frameCache, x0, y0 int, lines []string, titled bool) {
+	boxStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	titleStyle := boxStyle.Bold(true)
+
+	boxW, boxH := boxSize(lines)
+	for y := 0; y < boxH; y++ {
+		for x := 0; x < boxW; x++ {
+			ch := ' '
+			switch {
+			case y == 0 && x == 0:
+				ch = '┌'
+			case y == 0 && x == boxW-1:
+				ch = '┐'
+			case y == boxH-1 && x == 0:
+				ch = '└'
+			case y == boxH-1 && x == boxW-1:
+				ch = '┘'
+			case y == 0 || y == boxH-1:
+				ch = '─'
+			case x == 0 || x == boxW-1:
+				ch = '│'
+			}
+			cache.set(s, x0+x, y0+y, ch, boxStyle)
+		}
+	}
+	for i, l := range lines {
+		style := boxStyle
+		if i == 0 && titled {
+			style = titleStyle
+		}
+		for j, ch := range []rune(l) {
+			cache.set(s, x0+2+j, y0+1+i, ch, style)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestHelpLines_FollowKeymapAndFlags(t *testing.T) {
+	km, err := newKeymap(config{"keys": {"exit": "q", "intensity-up": "k, up"}})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("mouse", false, "")
+	fs.Bool("contribs", false, "")
+	if err := fs.Parse([]string{"--mouse"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	text := strings.Join(helpLines(km, fs, false), "\n")
+	for _, want := range []string{"q  ", "k, up", "raise the flames", "?", "Flags: --mouse"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("help text %q does not contain %q", text, want)
+		}
+	}
+	if strings.Contains(text, "any other key") || strings.Contains(text, "--contribs") {
+		t.Fatalf("help text %q mentions unconfigured binding or unset flag", text)
+	}
+}
+
+func TestHelpLines_Playground(t *testing.T) {
+	km, err := newKeymap(config{})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	text := strings.Join(helpLines(km, flag.NewFlagSet("test", flag.ContinueOnError), true), "\n")
+	for _, want := range []string{"esc", "pause / resume", "Playground", "typing speed"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("help text %q does not contain %q", text, want)
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, "any other key") && strings.Contains(line, "quit") {
+			t.Fatalf("help line %q says any key quits in playground", line)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package fire implements the heat diffusion behind the yule log.
+//
+// Heat lives in a flat float64 field, one value per cell, row-major with row
+// 0 at the top. Each step convolves the field with a Kernel, lets heat rise
+// from the row below (buoyancy) and then cools it.
+package fire
+
+// Tap is one weighted sample of a Kernel, relative to the cell being
+// updated. Positive DY samples rows below, positive DX samples to the right.
+type Tap struct {
+	DX, DY int
+	Weight float64
+}
+
+// Kernel is the convolution applied to the heat field every step. Samples
+// that fall outside the field read as zero heat.
+type Kernel []Tap
+
+// DefaultKernel averages a cell with its right, lower and lower-right
+// neighbours, the same update as the classic curses fire.
+var DefaultKernel = Kernel{
+	{DX: 0, DY: 0, Weight: 0.25},
+	{DX: 1, DY: 0, Weight: 0.25},
+	{DX: 0, DY: 1, Weight: 0.25},
+	{DX: 1, DY: 1, Weight: 0.25},
+}
+
+// Params controls how heat spreads and fades.
+type Params struct {
+	Kernel Kernel
+	// Cooling is subtracted from every cell each step.
+	Cooling float64
+	// CoolingMap optionally adds per-cell cooling on top of Cooling. It is
+	// indexed like the field and ignored unless it has the same length.
+	CoolingMap []float64
+	// Buoyancy blends in this fraction of the heat directly below a cell,
+	// making flames climb faster. 0 disables it.
+	Buoyancy float64
+}
+
+// DefaultParams returns parameters tuned to look like the original integer
+// simulation, whose truncating division did the cooling.
+func DefaultParams() Params {
+	return Params{
+		Kernel:  DefaultKernel,
+		Cooling: 0.35,
+	}
+}
+
+// Step writes one diffusion step of src into dst. Both must hold w*h cells.
+func Step(dst, src []float64, w, h int, p Params) {
+	coolMap := p.CoolingMap
+	if len(coolMap) != w*h {
+		coolMap = nil
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var v float64
+			for _, t := range p.Kernel {
+				sx, sy := x+t.DX, y+t.DY
+				if sx < 0 || sy < 0 || sx >= w || sy >= h {
+					continue
+				}
+				v += src[sy*w+sx] * t.Weight
+			}
+			if p.Buoyancy != 0 {
+				below := 0.0
+				if y+1 < h {
+					below = src[(y+1)*w+x]
+				}
+				v += (below - v) * p.Buoyancy
+			}
+			i := y*w + x
+			v -= p.Cooling
+			if coolMap != nil {
+				v -= coolMap[i]
+			}
+			if v < 0 {
+				v = 0
+			}
+			dst[i] = v
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package fire
+
+import "testing"
+
+func TestStep_DefaultKernelAverages(t *testing.T) {
+	w, h := 3, 2
+	src := []float64{
+		0, 0, 0,
+		8, 4, 0,
+	}
+	dst := make([]float64, w*h)
+	Step(dst, src, w, h, Params{Kernel: DefaultKernel})
+	// Top-left averages itself, right, below and below-right.
+	if got, want := dst[0], 3.0; got != want {
+		t.Fatalf("dst[0] = %v, want %v", got, want)
+	}
+	// Samples past the bottom edge read as zero.
+	if got, want := dst[3], 3.0; got != want {
+		t.Fatalf("dst[3] = %v, want %v", got, want)
+	}
+}
+
+func TestStep_CoolingClampsAtZero(t *testing.T) {
+	w, h := 2, 1
+	src := []float64{1, 1}
+	dst := make([]float64, w*h)
+	Step(dst, src, w, h, Params{
+		Kernel:     Kernel{{Weight: 1}},
+		Cooling:    0.5,
+		CoolingMap: []float64{0, 2},
+	})
+	if dst[0] != 0.5 || dst[1] != 0 {
+		t.Fatalf("got %v, want [0.5 0]", dst)
+	}
+}
+
+func TestStep_BuoyancyPullsHeatUp(t *testing.T) {
+	w, h := 1, 2
+	src := []float64{0, 10}
+	dst := make([]float64, w*h)
+	Step(dst, src, w, h, Params{Kernel: Kernel{{Weight: 1}}, Buoyancy: 0.5})
+	if got, want := dst[0], 5.0; got != want {
+		t.Fatalf("dst[0] = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package fire
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A Layout decides where heat sources sit along the bottom row. It returns
+// the column for source i of n on the given step; columns outside
+// [0, width) are dropped.
+type Layout func(rng *rand.Rand, width, step, i, n int) int
+
+// LayoutNames lists the built-in layouts.
+var LayoutNames = []string{"random", "logs", "center", "edges", "wave"}
+
+// LayoutByName returns the built-in layout called name.
+func LayoutByName(name string) (Layout, bool) {
+	switch name {
+	case "random":
+		return RandomLayout, true
+	case "logs":
+		return LogsLayout, true
+	case "center":
+		return CenterLayout, true
+	case "edges":
+		return EdgesLayout, true
+	case "wave":
+		return WaveLayout, true
+	}
+	return nil, false
+}
+
+// RandomLayout scatters sources uniformly along the bottom row.
+func RandomLayout(rng *rand.Rand, width, step, i, n int) int {
+	return rng.Intn(width)
+}
+
+// LogsLayout gathers sources into two or three log-shaped clumps.
+func LogsLayout(rng *rand.Rand, width, step, i, n int) int {
+	logs := 2
+	if width >= 60 {
+		logs = 3
+	}
+	center := width * (2*(i%logs) + 1) / (2 * logs)
+	half := width / (logs * 3)
+	return center - half + rng.Intn(2*half+1)
+}
+
+// CenterLayout piles sources up in the middle.
+func CenterLayout(rng *rand.Rand, width, step, i, n int) int {
+	return width/2 + int(rng.NormFloat64()*float64(width)/8)
+}
+
+// EdgesLayout splits sources between the two sides.
+func EdgesLayout(rng *rand.Rand, width, step, i, n int) int {
+	in := int(math.Abs(rng.NormFloat64()) * float64(width) / 10)
+	if i%2 == 0 {
+		return in
+	}
+	return width - 1 - in
+}
+
+// WaveLayout sweeps a hot spot back and forth along the bottom row.
+func WaveLayout(rng *rand.Rand, width, step, i, n int) int {
+	center := (1 - math.Cos(float64(step)*0.02)) / 2 * float64(width-1)
+	return int(center + rng.NormFloat64()*float64(width)/16)
+}
@@ -0,0 +1,97 @@
+package fire
+
+import "math/rand"
+
+// Simulation owns a heat field and the sources feeding it from the bottom
+// row. It knows nothing about terminals; callers read Cells after each Step
+// and decide how to draw them.
+type Simulation struct {
+	// Params controls diffusion and cooling.
+	Params Params
+	// Sources is how many bottom-row cells are set to Power each step.
+	Sources int
+	// Power is the heat written by each source.
+	Power float64
+	// Layout places the sources; nil means RandomLayout.
+	Layout Layout
+
+	width, height int
+	cells, next   []float64
+	rng           *rand.Rand
+	steps         int
+}
+
+// NewSimulation returns a width×height simulation using DefaultParams, with
+// source positions drawn from a generator seeded with seed.
+func NewSimulation(width, height int, seed int64) *Simulation {
+	sim := &Simulation{
+		Params: DefaultParams(),
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+	sim.Resize(width, height)
+	return sim
+}
+
+// Resize changes the field dimensions, discarding all heat.
+func (sim *Simulation) Resize(width, height int) {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	sim.width, sim.height = width, height
+	sim.cells = make([]float64, width*height)
+	sim.next = make([]float64, width*height)
+}
+
+// Size returns the field dimensions.
+func (sim *Simulation) Size() (width, height int) {
+	return sim.width, sim.height
+}
+
+// Cells returns the heat field, row-major with row 0 at the top. The slice
+// is only valid until the next Step or Resize.
+func (sim *Simulation) Cells() []float64 {
+	return sim.cells
+}
+
+// Inject sets the bottom-row cell in column x to power.
+func (sim *Simulation) Inject(x int, power float64) {
+	if x < 0 || x >= sim.width || sim.height == 0 {
+		return
+	}
+	sim.cells[(sim.height-1)*sim.width+x] = power
+}
+
+// InjectAt sets the cell at x,y to power, e.g. to stoke the fire under a
+// mouse pointer. Positions outside the field are ignored.
+func (sim *Simulation) InjectAt(x, y int, power float64) {
+	if x < 0 || y < 0 || x >= sim.width || y >= sim.height {
+		return
+	}
+	sim.cells[y*sim.width+x] = power
+}
+
+// Step feeds the sources and advances the field by one diffusion step.
+func (sim *Simulation) Step() {
+	if sim.width == 0 || sim.height == 0 {
+		return
+	}
+	sim.generateHeat()
+	Step(sim.next, sim.cells, sim.width, sim.height, sim.Params)
+	sim.cells, sim.next = sim.next, sim.cells
+}
+
+// generateHeat lights Sources cells along the bottom row, placed by the
+// layout.
+func (sim *Simulation) generateHeat() {
+	layout := sim.Layout
+	if layout == nil {
+		layout = RandomLayout
+	}
+	for i := 0; i < sim.Sources; i++ {
+		sim.Inject(layout(sim.rng, sim.width, sim.steps, i, sim.Sources), sim.Power)
+	}
+	sim.steps++
+}
@@ -0,0 +1,133 @@
+package fire
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSimulation_InjectAndStep(t *testing.T) {
+	sim := NewSimulation(4, 3, 1)
+	sim.Inject(2, 40)
+	sim.Inject(-1, 40)
+	sim.Inject(4, 40)
+	cells := sim.Cells()
+	if got := cells[2*4+2]; got != 40 {
+		t.Fatalf("bottom row cell = %v, want 40", got)
+	}
+	sim.Step()
+	// Heat from the bottom row has diffused one row up.
+	if sim.Cells()[1*4+2] == 0 {
+		t.Fatalf("expected heat to rise after Step, got %v", sim.Cells())
+	}
+}
+
+func TestSimulation_InjectAt(t *testing.T) {
+	sim := NewSimulation(4, 3, 1)
+	sim.InjectAt(1, 0, 30)
+	sim.InjectAt(4, 0, 30)
+	sim.InjectAt(0, -1, 30)
+	want := make([]float64, 12)
+	want[1] = 30
+	for i, v := range sim.Cells() {
+		if v != want[i] {
+			t.Fatalf("cell %d = %v, want %v", i, v, want[i])
+		}
+	}
+}
+
+func TestSimulation_SourcesHeatBottomRow(t *testing.T) {
+	sim := NewSimulation(10, 5, 1)
+	sim.Sources = 3
+	sim.Power = 50
+	sim.Step()
+	total := 0.0
+	for _, v := range sim.Cells() {
+		total += v
+	}
+	if total == 0 {
+		t.Fatalf("expected sources to add heat")
+	}
+}
+
+func TestSimulation_Resize(t *testing.T) {
+	sim := NewSimulation(4, 3, 1)
+	sim.Inject(0, 10)
+	sim.Resize(6, 2)
+	if w, h := sim.Size(); w != 6 || h != 2 {
+		t.Fatalf("Size() = %d,%d, want 6,2", w, h)
+	}
+	if got := len(sim.Cells()); got != 12 {
+		t.Fatalf("len(Cells()) = %d, want 12", got)
+	}
+	for _, v := range sim.Cells() {
+		if v != 0 {
+			t.Fatalf("expected Resize to clear heat")
+		}
+	}
+	sim.Resize(0, 0)
+	sim.Step() // must not panic on an empty field
+}
+
+func BenchmarkSimulationStep(b *testing.B) {
+	sim := NewSimulation(300, 80, 1)
+	sim.Sources = 300 / 9
+	sim.Power = 65
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sim.Step()
+	}
+}
+
+func TestLayouts_StayOnTheBottomRow(t *testing.T) {
+	const width, height = 30, 4
+	for _, name := range LayoutNames {
+		layout, ok := LayoutByName(name)
+		if !ok {
+			t.Fatalf("LayoutByName(%q) not found", name)
+		}
+		sim := NewSimulation(width, height, 1)
+		sim.Layout = layout
+		sim.Sources = 10
+		sim.Power = 50
+		// Feed the sources without diffusing, so every cell set is one a
+		// source wrote.
+		for i := 0; i < 50; i++ {
+			sim.generateHeat()
+		}
+		bottom := 0.0
+		for i, v := range sim.Cells() {
+			if i/width < height-1 {
+				if v != 0 {
+					t.Fatalf("%s: heat at %d,%d, above the bottom row", name, i%width, i/width)
+				}
+				continue
+			}
+			bottom += v
+		}
+		if bottom == 0 {
+			t.Errorf("%s: expected sources to heat the bottom row", name)
+		}
+	}
+	if _, ok := LayoutByName("spiral"); ok {
+		t.Errorf("expected unknown layout to be missing")
+	}
+}
+
+func TestLayouts_Shape(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const width = 90
+	for i := 0; i < 200; i++ {
+		if x := EdgesLayout(rng, width, 0, i, 200); x > width/3 && x < width*2/3 {
+			t.Fatalf("edges placed a source in the middle at %d", x)
+		}
+		if x := LogsLayout(rng, width, 0, i, 200); x < 0 || x >= width {
+			t.Fatalf("logs placed a source off the row at %d", x)
+		}
+	}
+	// The wave's hot spot starts at the left and reaches the right.
+	left, right := WaveLayout(rng, width, 0, 0, 1), WaveLayout(rng, width, 157, 0, 1)
+	if left > width/4 || right < width*3/4 {
+		t.Fatalf("wave at %d then %d, want it to sweep left to right", left, right)
+	}
+}
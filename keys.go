@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// action is something a key press can trigger.
+type action string
+
+const (
+	actionExit          action = "exit"
+	actionIntensityUp   action = "intensity-up"
+	actionIntensityDown action = "intensity-down"
+	actionPause         action = "pause"
+	actionStoke         action = "stoke"
+	actionThemeCycle    action = "theme-cycle"
+	actionScreenshot    action = "screenshot"
+	actionHelp          action = "help"
+)
+
+// actions lists every bindable action in the order they are documented.
+var actions = []action{
+	actionExit,
+	actionIntensityUp,
+	actionIntensityDown,
+	actionPause,
+	actionStoke,
+	actionThemeCycle,
+	actionScreenshot,
+	actionHelp,
+}
+
+// actionDescriptions is shown next to each action's keys in the help overlay.
+var actionDescriptions = map[action]string{
+	actionExit:          "quit",
+	actionIntensityUp:   "raise the flames",
+	actionIntensityDown: "lower the flames",
+	actionPause:         "pause / resume",
+	actionStoke:         "stoke the fire",
+	actionThemeCycle:    "next theme",
+	actionScreenshot:    "save a screenshot to ~/Pictures",
+	actionHelp:          "show this help",
+}
+
+// anyKey is the binding that matches every key not bound to something else.
+const anyKey = "any"
+
+// defaultBindings reproduces the classic controls: arrows adjust the
+// intensity, p pauses, t changes theme, s takes a screenshot, ? shows help
+// and any other key exits.
+var defaultBindings = map[action][]string{
+	actionExit:          {anyKey},
+	actionIntensityUp:   {"up"},
+	actionIntensityDown: {"down"},
+	actionPause:         {"p"},
+	actionThemeCycle:    {"t"},
+	actionScreenshot:    {"s"},
+	actionHelp:          {"?"},
+}
+
+// keyNames maps the names accepted in the [keys] config section to tcell
+// keys. Anything else must be a single character.
+var keyNames = map[string]tcell.Key{
+	"up":        tcell.KeyUp,
+	"down":      tcell.KeyDown,
+	"left":      tcell.KeyLeft,
+	"right":     tcell.KeyRight,
+	"enter":     tcell.KeyEnter,
+	"esc":       tcell.KeyEscape,
+	"tab":       tcell.KeyTab,
+	"backspace": tcell.KeyBackspace2,
+	"home":      tcell.KeyHome,
+	"end":       tcell.KeyEnd,
+	"pgup":      tcell.KeyPgUp,
+	"pgdn":      tcell.KeyPgDn,
+}
+
+// keySpec identifies a key press: a special key, or a rune for KeyRune.
+type keySpec struct {
+	key tcell.Key
+	ch  rune
+}
+
+func parseKeySpec(name string) (keySpec, error) {
+	if name == "space" {
+		return keySpec{key: tcell.KeyRune, ch: ' '}, nil
+	}
+	if k, ok := keyNames[strings.ToLower(name)]; ok {
+		return keySpec{key: k}, nil
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		ch, _ := utf8.DecodeRuneInString(name)
+		return keySpec{key: tcell.KeyRune, ch: ch}, nil
+	}
+	return keySpec{}, fmt.Errorf("unknown key %q", name)
+}
+
+func specFor(ev *tcell.EventKey) keySpec {
+	if ev.Key() == tcell.KeyRune {
+		return keySpec{key: tcell.KeyRune, ch: ev.Rune()}
+	}
+	return keySpec{key: ev.Key()}
+}
+
+// keymap resolves key presses to actions.
+type keymap struct {
+	bindings map[keySpec]action
+	// names keeps each action's keys as configured, for display.
+	names map[action][]string
+	// fallback is triggered by keys with no binding of their own.
+	fallback action
+}
+
+// newKeymap builds a keymap from the defaults overlaid with the [keys]
+// section of cfg. Configuring an action replaces its default keys.
+func newKeymap(cfg config) (*keymap, error) {
+	names := map[action][]string{}
+	for a, keys := range defaultBindings {
+		names[a] = keys
+	}
+	for name, value := range cfg["keys"] {
+		a := action(name)
+		if !knownAction(a) {
+			return nil, fmt.Errorf("[keys]: unknown action %q", name)
+		}
+		names[a] = configList(value)
+	}
+
+	km := &keymap{bindings: map[keySpec]action{}, names: names}
+	// Bind in a fixed order so conflicts are reported deterministically.
+	for _, a := range actions {
+		for _, name := range names[a] {
+			if name == anyKey {
+				km.fallback = a
+				continue
+			}
+			spec, err := parseKeySpec(name)
+			if err != nil {
+				return nil, fmt.Errorf("[keys] %s: %w", a, err)
+			}
+			if other, ok := km.bindings[spec]; ok {
+				return nil, fmt.Errorf("[keys]: %q is bound to both %s and %s", name, other, a)
+			}
+			km.bindings[spec] = a
+		}
+	}
+	return km, nil
+}
+
+func knownAction(a action) bool {
+	for _, known := range actions {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the action bound to ev, if any. Ctrl-C always exits so a
+// config without an exit binding can't trap the user.
+func (km *keymap) lookup(ev *tcell.EventKey) (action, bool) {
+	if ev.Key() == tcell.KeyCtrlC {
+		return actionExit, true
+	}
+	if a, ok := km.bindings[specFor(ev)]; ok {
+		return a, true
+	}
+	if km.fallback != "" {
+		return km.fallback, true
+	}
+	return "", false
+}
+
+// playgroundLookup is lookup for --playground, where keys without a
+// binding of their own are typing rather than the any-key fallback. Esc
+// exits there unless exit has keys of its own configured.
+func (km *keymap) playgroundLookup(ev *tcell.EventKey) (action, bool) {
+	if ev.Key() == tcell.KeyCtrlC {
+		return actionExit, true
+	}
+	if a, ok := km.bindings[specFor(ev)]; ok {
+		return a, true
+	}
+	if ev.Key() == tcell.KeyEscape && !km.exitBound() {
+		return actionExit, true
+	}
+	return "", false
+}
+
+// exitBound reports whether exit has keys other than the any-key fallback.
+func (km *keymap) exitBound() bool {
+	for _, a := range km.bindings {
+		if a == actionExit {
+			return true
+		}
+	}
+	return false
+}
+
+// keysFor returns the keys bound to a, as written in the config.
+func (km *keymap) keysFor(a action) []string {
+	return km.names[a]
+}
+
+// actionEvent delivers a bound action to the active Visualization.
+type actionEvent struct {
+	tcell.EventTime
+	action action
+}
+
+func newActionEvent(a action) *actionEvent {
+	ev := &actionEvent{action: a}
+	ev.SetEventNow()
+	return ev
+}
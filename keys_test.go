@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func runeKey(ch rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone)
+}
+
+func TestKeymap_Defaults(t *testing.T) {
+	km, err := newKeymap(config{})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	if a, _ := km.lookup(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)); a != actionIntensityUp {
+		t.Fatalf("up = %q, want %q", a, actionIntensityUp)
+	}
+	if a, _ := km.lookup(runeKey('p')); a != actionPause {
+		t.Fatalf("p = %q, want %q", a, actionPause)
+	}
+	if a, _ := km.lookup(runeKey('x')); a != actionExit {
+		t.Fatalf("x = %q, want any key to exit by default", a)
+	}
+}
+
+func TestKeymap_Configured(t *testing.T) {
+	km, err := newKeymap(config{"keys": {
+		"exit":           "q",
+		"intensity-up":   "k",
+		"intensity-down": `["j", "down"]`,
+	}})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	if a, _ := km.lookup(runeKey('q')); a != actionExit {
+		t.Fatalf("q = %q, want exit", a)
+	}
+	if a, _ := km.lookup(runeKey('k')); a != actionIntensityUp {
+		t.Fatalf("k = %q, want intensity-up", a)
+	}
+	if a, _ := km.lookup(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)); a != actionIntensityDown {
+		t.Fatalf("down = %q, want intensity-down", a)
+	}
+	if _, ok := km.lookup(runeKey('x')); ok {
+		t.Fatalf("expected unbound keys not to exit once exit is configured")
+	}
+	if a, _ := km.lookup(tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModCtrl)); a != actionExit {
+		t.Fatalf("ctrl-c = %q, want exit", a)
+	}
+}
+
+func TestKeymap_Errors(t *testing.T) {
+	for _, keys := range []map[string]string{
+		{"explode": "x"},
+		{"exit": "nosuchkey"},
+		{"exit": "q", "stoke": "q"},
+	} {
+		if _, err := newKeymap(config{"keys": keys}); err == nil {
+			t.Fatalf("expected error for %v", keys)
+		}
+	}
+}
+
+func TestKeymap_Playground(t *testing.T) {
+	km, err := newKeymap(config{})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	// Bound keys keep working while typing; the rest count as typing.
+	for _, tc := range []struct {
+		ev   *tcell.EventKey
+		want action
+		ok   bool
+	}{
+		{runeKey('p'), actionPause, true},
+		{runeKey('t'), actionThemeCycle, true},
+		{runeKey('?'), actionHelp, true},
+		{runeKey('a'), "", false},
+		{runeKey(' '), "", false},
+		{tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), actionExit, true},
+		{tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModNone), actionExit, true},
+	} {
+		if a, ok := km.playgroundLookup(tc.ev); a != tc.want || ok != tc.ok {
+			t.Errorf("%s = %q, %v; want %q, %v", tc.ev.Name(), a, ok, tc.want, tc.ok)
+		}
+	}
+
+	// A configured exit key replaces Esc.
+	km, err = newKeymap(config{"keys": {"exit": "q"}})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	if a, ok := km.playgroundLookup(runeKey('q')); a != actionExit || !ok {
+		t.Errorf("q = %q, %v; want the configured exit", a, ok)
+	}
+	if _, ok := km.playgroundLookup(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)); ok {
+		t.Errorf("esc should be typing once exit has its own keys")
+	}
+}
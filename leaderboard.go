@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// leaderboardSize is how many committers --leaderboard ranks.
+const leaderboardSize = 5
+
+// leaderboardSince is the window --leaderboard counts commits over.
+const leaderboardSince = "30.days"
+
+// contributor is a committer and how many commits they made.
+type contributor struct {
+	name    string
+	commits int
+}
+
+// authorColors are the colors committers are drawn in, chosen by name so
+// everyone keeps theirs between runs. There must be at least
+// leaderboardSize of them so every ranked committer gets their own.
+var authorColors = []tcell.Color{
+	tcell.NewRGBColor(255, 138, 101),
+	tcell.NewRGBColor(255, 213, 79),
+	tcell.NewRGBColor(129, 199, 132),
+	tcell.NewRGBColor(100, 181, 246),
+	tcell.NewRGBColor(186, 104, 200),
+	tcell.NewRGBColor(77, 208, 225),
+	tcell.NewRGBColor(240, 98, 146),
+	tcell.NewRGBColor(174, 213, 129),
+}
+
+// authorColor returns the index into authorColors a committer's name
+// hashes to.
+func authorColor(name string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int(h.Sum32() % uint32(len(authorColors)))
+}
+
+// rankedAuthorColors gives each committer on the board a distinct color.
+// Everyone starts from the color their name hashes to; if a higher-ranked
+// committer already has it, they take the next free one.
+func rankedAuthorColors(board []contributor) map[string]tcell.Color {
+	colors := make(map[string]tcell.Color, len(board))
+	taken := make([]bool, len(authorColors))
+	for _, c := range board {
+		if _, ok := colors[c.name]; ok {
+			continue
+		}
+		i := authorColor(c.name)
+		for n := 0; taken[i] && n < len(authorColors); n++ {
+			i = (i + 1) % len(authorColors)
+		}
+		taken[i] = true
+		colors[c.name] = authorColors[i]
+	}
+	return colors
+}
+
+// authorStyle returns the style for a committer: their color on the board
+// if they're ranked, otherwise the one their name hashes to.
+func authorStyle(name string, ranked map[string]tcell.Color) tcell.Style {
+	color, ok := ranked[name]
+	if !ok {
+		color = authorColors[authorColor(name)]
+	}
+	return tcell.StyleDefault.Foreground(color)
+}
+
+// colorByAuthor returns a copy of cards where every card about a committer
+// has that committer's style, unless it already has a style of its own.
+func colorByAuthor(cards []tickerCard, board []contributor) []tickerCard {
+	ranked := rankedAuthorColors(board)
+	colored := make([]tickerCard, len(cards))
+	copy(colored, cards)
+	for i := range colored {
+		if colored[i].author != "" && colored[i].style == (tcell.Style{}) {
+			colored[i].style = authorStyle(colored[i].author, ranked)
+		}
+	}
+	return colored
+}
+
+// loadLeaderboard ranks the top n committers of the repository in dir over
+// the last leaderboardSince.
+func loadLeaderboard(dir string, n int) []contributor {
+	// shortlog reads stdin unless given a revision.
+	cmd := exec.Command("git", "shortlog", "-sn", "--since="+leaderboardSince, "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	board := parseShortlog(string(out))
+	if len(board) > n {
+		board = board[:n]
+	}
+	return board
+}
+
+// boardEvent delivers a refreshed leaderboard to the main loop.
+type boardEvent struct {
+	tcell.EventTime
+	board []contributor
+}
+
+// parseShortlog parses `git shortlog -sn` output.
+func parseShortlog(out string) []contributor {
+	var board []contributor
+	for _, line := range strings.Split(out, "\n") {
+		count, name, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			continue
+		}
+		board = append(board, contributor{name: strings.TrimSpace(name), commits: n})
+	}
+	return board
+}
+
+// drawLeaderboard draws the ranked committers in a panel in the top-right
+// corner, just below the paused badge.
+func drawLeaderboard(s tcell.Screen, cache *frameCache, width int, board []contributor) {
+	if len(board) == 0 {
+		return
+	}
+	const title = " Top committers, 30 days "
+	boxStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+
+	innerW := len([]rune(title))
+	for i, c := range board {
+		row := fmt.Sprintf("%d. %s  %d", i+1, truncate(c.name, 20), c.commits)
+		innerW = max(innerW, len([]rune(row))+2)
+	}
+	boxW := innerW + 2
+	x0, y0 := width-boxW-1, 1
+	ranked := rankedAuthorColors(board)
+
+	border := func(left, fill, right rune, y int) {
+		cache.set(s, x0, y, left, boxStyle)
+		for x := 1; x < boxW-1; x++ {
+			cache.set(s, x0+x, y, fill, boxStyle)
+		}
+		cache.set(s, x0+boxW-1, y, right, boxStyle)
+	}
+	border('┌', '─', '┐', y0)
+	for i, ch := range []rune(title) {
+		cache.set(s, x0+1+i, y0, ch, boxStyle.Bold(true))
+	}
+	for i, c := range board {
+		y := y0 + 1 + i
+		border('│', ' ', '│', y)
+		// The name in its committer color, the rest in white.
+		rank := strconv.Itoa(i+1) + ". "
+		name := []rune(truncate(c.name, 20))
+		x := x0 + 2
+		for _, ch := range rank {
+			cache.set(s, x, y, ch, boxStyle)
+			x++
+		}
+		fg, _, _ := authorStyle(c.name, ranked).Decompose()
+		for _, ch := range name {
+			cache.set(s, x, y, ch, boxStyle.Foreground(fg))
+			x++
+		}
+		count := strconv.Itoa(c.commits)
+		for j, ch := range count {
+			cache.set(s, x0+boxW-2-len(count)+j, y, ch, boxStyle)
+		}
+	}
+	border('└', '─', '┘', y0+len(board)+1)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseShortlog(t *testing.T) {
+	board := parseShortlog("    42\tAda Lovelace\n     7\tGrace Hopper\n")
+	if len(board) != 2 || board[0] != (contributor{"Ada Lovelace", 42}) || board[1] != (contributor{"Grace Hopper", 7}) {
+		t.Fatalf("got %+v", board)
+	}
+}
+
+func TestColorByAuthor(t *testing.T) {
+	cards := []tickerCard{
+		{msg: "Fix bug", author: "Ada"},
+		{msg: "v1.0.0", style: latestReleaseStyle},
+		{msg: "Add tests", author: "Ada"},
+	}
+	cards = colorByAuthor(cards, nil)
+	if cards[0].style != authorStyle("Ada", nil) || cards[2].style != cards[0].style {
+		t.Errorf("Ada's commits should share her color")
+	}
+	if cards[1].style != latestReleaseStyle {
+		t.Errorf("cards with their own style should keep it")
+	}
+	if authorStyle("Ada", nil) == (tcell.Style{}) {
+		t.Errorf("expected a color")
+	}
+}
+
+func TestRankedAuthorColors_Distinct(t *testing.T) {
+	// Find two names that hash to the same color.
+	first := "committer-0"
+	second := ""
+	for i := 1; second == ""; i++ {
+		if name := fmt.Sprintf("committer-%d", i); authorColor(name) == authorColor(first) {
+			second = name
+		}
+	}
+	board := []contributor{{first, 9}, {second, 8}, {"c", 7}, {"d", 6}, {"e", 5}}
+	ranked := rankedAuthorColors(board)
+	if ranked[first] != authorColors[authorColor(first)] {
+		t.Errorf("the top committer should keep their hashed color")
+	}
+	seen := map[tcell.Color]string{}
+	for _, c := range board {
+		if other, ok := seen[ranked[c.name]]; ok {
+			t.Errorf("%s and %s share a color", other, c.name)
+		}
+		seen[ranked[c.name]] = c.name
+	}
+	cards := colorByAuthor([]tickerCard{{msg: "Fix", author: second}}, board)
+	if fg, _, _ := cards[0].style.Decompose(); fg != ranked[second] {
+		t.Errorf("ticker color %v doesn't match the board's %v", fg, ranked[second])
+	}
+}
+
+func TestDrawLeaderboard(t *testing.T) {
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer sim.Fini()
+	sim.SetSize(50, 8)
+
+	drawLeaderboard(sim, newFrameCache(50, 8), 50, []contributor{{"Ada", 42}})
+	if ch, _, _, _ := sim.GetContent(48, 1); ch != '┐' {
+		t.Errorf("expected the panel's corner in the top right, got %q", ch)
+	}
+	want, _, _ := authorStyle("Ada", nil).Decompose()
+	if ch, _, style, _ := sim.GetContent(27, 2); ch != 'A' {
+		t.Errorf("expected the name at 27,2, got %q", ch)
+	} else if fg, _, _ := style.Decompose(); fg != want {
+		t.Errorf("name drawn in %v, want the committer's color %v", fg, want)
+	}
+}
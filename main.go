@@ -2,8 +2,8 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
-	"math/rand"
 	"os"
 	"os/exec"
 	"strconv"
@@ -11,13 +11,26 @@ import (
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+
+	"gh-yule-log/internal/fire"
 )
 
 // parseGitLogToTicker converts `git log` output into two long strings:
 // one for commit messages and one for "by AUTHOR REL_TIME" meta lines.
 func parseGitLogToTicker(logOutput string) (string, string, bool) {
+	cards := parseGitLogCards(logOutput)
+	msg, meta, _ := layoutCards(cards)
+	return msg, meta, len(cards) > 0
+}
+
+// gitLogFormat is the `git log` format parseGitLogCards reads. The commit
+// time after the hash is optional.
+const gitLogFormat = "%h %at%x09%an%x09%ar%x09%s"
+
+// parseGitLogCards converts `git log` output into ticker cards.
+func parseGitLogCards(logOutput string) []tickerCard {
 	lines := strings.Split(strings.TrimSpace(logOutput), "\n")
-	var msgSegs, metaSegs []string
+	var cards []tickerCard
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -27,27 +40,24 @@ func parseGitLogToTicker(logOutput string) (string, string, bool) {
 		if len(parts) != 4 {
 			continue
 		}
-		_, author, relTime, subject := parts[0], parts[1], parts[2], parts[3]
-		message := subject
-		meta := "by " + author + " " + relTime
-		// Fixed card width so message/meta line up as columns.
-		// Use rune counts so multi-byte characters don't break alignment.
-		msgRunes := []rune(message)
-		metaRunes := []rune(meta)
-		segmentWidth := len(msgRunes)
-		if len(metaRunes) > segmentWidth {
-			segmentWidth = len(metaRunes)
+		commit, author, relTime, subject := parts[0], parts[1], parts[2], parts[3]
+		card := tickerCard{msg: subject, meta: "by " + author + " " + relTime, author: author}
+		if _, at, ok := strings.Cut(commit, " "); ok {
+			if secs, err := strconv.ParseInt(at, 10, 64); err == nil {
+				card.when = time.Unix(secs, 0)
+			}
 		}
-		segmentWidth += 4
-		msgSegs = append(msgSegs, padRight(message, segmentWidth))
-		metaSegs = append(metaSegs, padRight(meta, segmentWidth))
-	}
-	if len(msgSegs) == 0 {
-		return "", "", false
+		cards = append(cards, card)
 	}
-	return strings.Join(msgSegs, ""), strings.Join(metaSegs, ""), true
+	return cards
 }
 
+// frameDelay is the time between frames.
+const frameDelay = 30 * time.Millisecond
+
+// gaugeDuration is how long the intensity gauge shows after a change.
+const gaugeDuration = 1500 * time.Millisecond
+
 func padRight(s string, n int) string {
 	rs := []rune(s)
 	if len(rs) >= n {
@@ -56,12 +66,12 @@ func padRight(s string, n int) string {
 	return s + strings.Repeat(" ", n-len(rs))
 }
 
-// buildGitTickerText runs git log and returns the scrolling texts.
-func buildGitTickerText(maxCommits int) (string, string, bool) {
+// gitLogCards runs git log and returns a ticker card per commit.
+func gitLogCards(maxCommits int) []tickerCard {
 	args := []string{
 		"log",
 		"-n", strconv.Itoa(maxCommits),
-		"--pretty=format:%h%x09%an%x09%ar%x09%s",
+		"--pretty=format:" + gitLogFormat,
 	}
 	cmd := exec.Command("git", args...)
 	if dir := os.Getenv("YULE_LOG_GIT_DIR"); dir != "" {
@@ -69,80 +79,275 @@ func buildGitTickerText(maxCommits int) (string, string, bool) {
 	}
 	out, err := cmd.Output()
 	if err != nil {
-		return "", "", false
+		return nil
 	}
-	return parseGitLogToTicker(string(out))
+	return parseGitLogCards(string(out))
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "status-widget":
+			if err := runStatusWidget(os.Args[2:]); err != nil {
+				log.Fatalf("status-widget: %v", err)
+			}
+			return
+		case "version":
+			if err := runVersion(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("version: %v", err)
+			}
+			return
+		case "preview":
+			if err := runPreview(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("preview: %v", err)
+			}
+			return
+		case "bench":
+			if err := runBench(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("bench: %v", err)
+			}
+			return
+		case "ctl":
+			if err := runCtl(os.Args[2:], os.Stderr); err != nil {
+				log.Fatalf("ctl: %v", err)
+			}
+			return
+		case "doctor":
+			if err := runDoctor(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("doctor: %v", err)
+			}
+			return
+		}
+	}
+
 	// Parse command-line flags.
+	themeName := flag.String("theme", "", "Theme to start with: "+themeNames()+", or auto to pick one for the date")
 	contribs := flag.Bool("contribs", false, "Use GitHub contribution graph-style visualization")
+	mouse := flag.Bool("mouse", false, "Click and drag to stoke the fire; scroll to adjust intensity")
+	sound := flag.Bool("sound", false, "Play crackling fire sounds (needs paplay, pw-cat, aplay or sox)")
+	cycleKey := flag.String("cycle-key", "", "Key that cycles through themes (overrides theme-cycle in [keys])")
+	tickerSource := flag.String("ticker-source", "commits", "What the ticker shows: commits, releases, commits+releases, or blame (with --file)")
+	blameFile := flag.String("file", "", "File to show with --ticker-source blame")
+	leaderboard := flag.Bool("leaderboard", false, "Show the repository's top committers of the last 30 days, each in their own color")
+	playground := flag.Bool("playground", false, "Typing warm-up: type to grow the flames, Esc quits")
+	sourceLayout := flag.String("source-layout", "", "Where the heat comes from: "+strings.Join(fire.LayoutNames, ", ")+" (default: the theme's)")
+	orientFlag := flag.String("orientation", "up", "Which way the flames go: up, down (a waterfall from the top), left or right")
+	message := flag.String("message", "", "Away message to show above the flames, e.g. \"Back at 14:00\" (see message in the config)")
+	messageTime := flag.Bool("message-time", false, "Show how long you've been away under --message")
+	burnDiff := flag.Bool("burn-diff", false, "Watch your latest changes burn: scroll the diff through the flames")
+	rotate := flag.Duration("rotate", 0, "Switch to the next theme this often, e.g. 10m (see rotate in the config)")
+	allPanes := flag.Bool("all-panes", false, "Spread one fire across every pane of the current tmux window")
+	paneView := flag.String("pane-view", "", "Internal: this pane's place in the --all-panes canvas")
+	syncSpec := flag.String("sync", "", "Internal: shared seed, start time and channel for --all-panes")
+	showVersion := flag.Bool("version", false, "Print version information and exit")
 	flag.Parse()
 
-	rand.Seed(time.Now().UnixNano())
+	if *showVersion {
+		fmt.Println(currentVersion())
+		return
+	}
+	var pane *paneSync
+	fatalf := log.Fatalf
+	if *paneView != "" {
+		var err error
+		if pane, err = parsePaneSync(*paneView, *syncSpec); err != nil {
+			log.Fatalf("%v", err)
+		}
+		defer pane.finish()
+		// A renderer that gives up must still tell the orchestrator, or
+		// the panes are never put back.
+		fatalf = func(format string, v ...any) {
+			log.Printf(format, v...)
+			pane.finish()
+			os.Exit(1)
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fatalf("reading config: %v", err)
+	}
+	if *cycleKey != "" {
+		cfg.set("keys", string(actionThemeCycle), *cycleKey)
+	}
+	keys, err := newKeymap(cfg)
+	if err != nil {
+		fatalf("reading config: %v", err)
+	}
+	if err := checkRotateInterval(*rotate); err != nil {
+		fatalf("%v", err)
+	}
+	orient, err := parseOrientation(*orientFlag)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if *sourceLayout != "" {
+		if _, ok := fire.LayoutByName(*sourceLayout); !ok {
+			fatalf("unknown --source-layout %q (want %s)", *sourceLayout, strings.Join(fire.LayoutNames, ", "))
+		}
+	}
+	startTheme := *themeName
+	if startTheme == "" {
+		startTheme, _ = cfg.get("", "theme")
+	}
+	if *contribs {
+		startTheme = "contribs"
+	}
+	switch startTheme {
+	case "":
+		startTheme = "fire"
+	case "auto":
+		rules, err := seasonRules(cfg)
+		if err != nil {
+			fatalf("reading config: %v", err)
+		}
+		startTheme = autoTheme(rules, time.Now())
+	}
+	rotation, err := rotationThemes(cfg)
+	if err != nil {
+		fatalf("reading config: %v", err)
+	}
+	intensity := defaultIntensity
+	if v, ok := cfg.get("", "intensity"); ok {
+		if intensity, err = strconv.Atoi(v); err != nil {
+			fatalf("reading config: intensity: %v", err)
+		}
+	}
+	colorSetting, _ := cfg.get("", "color")
+	forcedColors, forced, err := parseColorMode(colorSetting)
+	if err != nil {
+		fatalf("reading config: %v", err)
+	}
+	if forced {
+		forceColorMode(forcedColors)
+	}
+
+	themeIdx, ok := themeIndex(startTheme)
+	if !ok {
+		fatalf("unknown theme %q (want %s or auto)", startTheme, themeNames())
+	}
+	if _, err := tickerSources(*tickerSource, *blameFile); err != nil {
+		fatalf("%v", err)
+	}
+	if *allPanes {
+		// Everything above has been checked, so the renderers won't
+		// fail on it. Mouse and sound only make sense once, not in
+		// every pane.
+		forward := forwardedFlags(flag.CommandLine, "all-panes", "mouse", "sound")
+		if err := runAllPanes(forward); err != nil {
+			log.Fatalf("all-panes: %v", err)
+		}
+		return
+	}
+
+	away := awayMessage{text: *message}
+	if away.text == "" {
+		away.text, _ = cfg.get("", "message")
+	}
+	if *messageTime {
+		away.since = time.Now()
+	}
+
+	cards, err := loadTickerCards(*tickerSource, *blameFile)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	haveTicker := len(cards) > 0
+	var board []contributor
+	var refreshingBoard bool
+	if *leaderboard {
+		board = loadLeaderboard(gitDir(), leaderboardSize)
+	}
+	// tickerCards colors the cards to match the leaderboard, so the ticker
+	// and the board agree on everyone's color.
+	tickerCards := func(cards []tickerCard) []tickerCard {
+		if *leaderboard {
+			return colorByAuthor(cards, board)
+		}
+		return cards
+	}
 
 	s, err := tcell.NewScreen()
 	if err != nil {
-		log.Fatalf("creating screen: %v", err)
+		fatalf("creating screen: %v", err)
 	}
 	if err := s.Init(); err != nil {
-		log.Fatalf("initializing screen: %v", err)
+		fatalf("initializing screen: %v", err)
 	}
 	defer s.Fini()
 
 	s.Clear()
 	s.HideCursor()
+	if *mouse {
+		s.EnableMouse()
+	}
 
 	width, height := s.Size()
 	if width <= 0 || height <= 0 {
 		return
 	}
 
-	size := width * height
-	buffer := make([]int, size+width+1)
-
-	var chars []rune
-	var styles []tcell.Style
+	cache := newFrameCache(width, height)
+	seed := time.Now().UnixNano()
+	if pane != nil {
+		// Draw this pane's piece of the window-sized canvas.
+		cache.originX, cache.originY = pane.x, pane.y
+		width, height = pane.canvasWidth, pane.canvasHeight
+		seed = pane.seed
+	}
 
-	if *contribs {
-		// GitHub contribution graph-style glyphs and colors.
-		// Use varied glyphs for different intensity levels (10 total to match original behavior)
-		chars = []rune{' ', '⬝', '⬝', '⯀', '⯀', '◼', '◼', '■', '■', '■'}
-		// GitHub green palette: empty, low (#9be9a8), medium (#40c463), high (#30a14e), very high (#216e39)
-		styles = []tcell.Style{
-			tcell.StyleDefault.Foreground(tcell.ColorBlack),                 // index 0 (not used by style selection)
-			tcell.StyleDefault.Foreground(tcell.NewRGBColor(155, 233, 168)), // #9be9a8 (low)
-			tcell.StyleDefault.Foreground(tcell.NewRGBColor(64, 196, 99)),   // #40c463 (medium)
-			tcell.StyleDefault.Foreground(tcell.NewRGBColor(48, 161, 78)),   // #30a14e (high)
-			tcell.StyleDefault.Foreground(tcell.NewRGBColor(33, 110, 57)),   // #216e39 (very high)
+	colors := screenColorMode(s)
+	if forced {
+		colors = forcedColors
+	}
+	// newVis builds theme idx's visualization for the current canvas.
+	newVis := func(idx int, seed int64) Visualization {
+		layout := *sourceLayout
+		if layout == "" {
+			layout = themes[idx].layout
 		}
-	} else {
-		// Original fire-style glyphs and colors.
-		chars = []rune{' ', '.', ':', '^', '*', 'x', 's', 'S', '#', '$'}
-		// Colors: dark red -> bright yellow/white.
-		styles = []tcell.Style{
-			tcell.StyleDefault.Foreground(tcell.ColorBlack),
-			tcell.StyleDefault.Foreground(tcell.ColorMaroon),
-			tcell.StyleDefault.Foreground(tcell.ColorRed),
-			tcell.StyleDefault.Foreground(tcell.ColorDarkOrange),
-			tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true),
+		v := newOrientedVisualization(themes[idx].newVisualization(visOptions{seed: seed, colors: colors, layout: layout}), orient)
+		v.Resize(width, height)
+		if ic, ok := v.(intensityControl); ok {
+			ic.SetIntensity(intensity)
 		}
+		return v
+	}
+	vis := newVis(themeIdx, seed)
+	if ic, ok := vis.(intensityControl); ok {
+		intensity = ic.Intensity()
 	}
+	// Compare against the clamped value, so an out-of-range setting isn't
+	// rewritten unless the user changes the height.
+	savedIntensity := intensity
+	// pickedIntensity is the last height the user chose with the keys or
+	// mouse; heights set through ctl aren't remembered.
+	pickedIntensity := intensity
+	var gaugeUntil time.Time
+	var snd *crackle
+	var mouseDown bool
+	if *sound {
+		snd = startCrackle()
+	}
+	var flash string
+	var flashUntil time.Time
 
-	msgText, metaText, haveTicker := buildGitTickerText(20)
-	msgRow := height - 2
-	metaRow := height - 1
-	tickerOffset := 0
+	tick := newTicker(tickerCards(cards))
+	// Reserve bottom two lines for git info if available.
+	reserved := 0
+	if haveTicker {
+		reserved = 2
+	}
+	var burn *burningDiff
+	if *burnDiff {
+		burn = &burningDiff{lines: loadBurnDiff(gitDir())}
+	}
+	paused := false
+	showHelp := false
+	help := helpLines(keys, flag.CommandLine, *playground)
+	var typing typingMeter
 	frame := 0
 	events := make(chan tcell.Event, 10)
-	heatPower := 65
-	heatSources := width / 9
-	// clamp heat values to reasonable ranges.
-	const (
-		minHeat = 10
-		maxHeat = 85
-		minSources = 1
-	)
 
 	go func() {
 		for {
@@ -153,120 +358,296 @@ func main() {
 			events <- ev
 		}
 	}()
+	// Control is best-effort: if another screensaver already has the
+	// socket, it keeps it. --all-panes renderers each get a private
+	// socket, which the orchestrator passes commands on to.
+	if ln, err := listenControl(events); err == nil {
+		defer ln.Close()
+	}
 
-	frameDelay := 30 * time.Millisecond
+	// --all-panes renderers rotate by frame number, which follows their
+	// shared clock, so they all switch on the same frame. A lone fire's
+	// frames take longer than frameDelay once drawing is counted, so it
+	// watches the clock instead.
+	rotateFrames := int(*rotate / frameDelay)
+	nextRotation := time.Now().Add(*rotate)
+	rotationPos := rotationPosition(rotation, themeIdx)
+
+	// applyCtl carries out a control command and replies, unless the reply
+	// has to wait for ticker cards to load. It reports whether to quit.
+	var pendingCtl []*ctlEvent
+	applyCtl := func(ev *ctlEvent) bool {
+		var err error
+		reply := ev.reply
+		switch ev.cmd.verb {
+		case "dismiss":
+			reply <- nil
+			return true
+		case "set-theme":
+			idx, ok := themeIndex(ev.cmd.arg)
+			if !ok {
+				err = fmt.Errorf("unknown theme %q (want %s)", ev.cmd.arg, themeNames())
+				break
+			}
+			themeIdx = idx
+			rotationPos = rotationPosition(rotation, themeIdx)
+			// Seeded from the frame so --all-panes renderers agree.
+			vis = newVis(themeIdx, seed+int64(frame))
+		case "set-intensity":
+			n, _ := strconv.Atoi(ev.cmd.arg)
+			if ic, ok := vis.(intensityControl); ok {
+				ic.SetIntensity(n)
+				// Show the gauge as for any change, but don't take it
+				// as the user's pick.
+				intensity = ic.Intensity()
+				gaugeUntil = time.Now().Add(gaugeDuration)
+			} else {
+				err = fmt.Errorf("theme %s has no intensity", themes[themeIdx].name)
+			}
+		case "show-message":
+			away.text = ev.cmd.arg
+		case "reload-ticker":
+			// git can be slow, so load the cards off the loop; the
+			// reply goes out once they're in.
+			go func(reply chan error) {
+				cards, err := loadTickerCards(*tickerSource, *blameFile)
+				tev := &tickerEvent{cards: cards, err: err, reply: reply}
+				tev.SetEventNow()
+				events <- tev
+			}(reply)
+			reply = nil
+		}
+		if reply != nil {
+			reply <- err
+		}
+		return false
+	}
 
 loop:
 	for {
-		// Non-blocking input check.
-		select {
-		case ev := <-events:
-			switch ev := ev.(type) {
-			// Handle arrow key presses to adjust heat scaling.
-			case *tcell.EventKey:
-				switch ev.Key() {
-				case tcell.KeyUp:
-					heatPower += 5
-					if heatPower > maxHeat {
-						heatPower = maxHeat
-					}
-					heatSources++
-					if heatSources > width {
-						heatSources = width
-					}
-				case tcell.KeyDown:
-					heatPower -= 5
-					if heatPower < minHeat {
-						heatPower = minHeat
-					}
-					if heatSources > minSources {
-						heatSources--
-					}
-				default:
-					break loop // any other key exits
-				}
-			case *tcell.EventResize:
-				width, height = s.Size()
-				if width <= 0 || height <= 0 {
-					break loop
-				}
-				size = width * height
-				buffer = make([]int, size+width+1)
-				msgRow = height - 2
-				metaRow = height - 1
-				heatSources = width / 9
+		var ev tcell.Event
+		if paused {
+			// Nothing moves while paused, so sleep until something happens.
+			ev = <-events
+		} else {
+			// Non-blocking input check.
+			select {
+			case ev = <-events:
+			default:
 			}
-		default:
 		}
 
-		// Inject heat on bottom row to be scaled by arrow keys.
-		for i := 0; i < heatSources; i++ {
-			idx := rand.Intn(width) + width*(height-1)
-			if idx >= 0 && idx < len(buffer) {
-				buffer[idx] = heatPower
+		switch ev := ev.(type) {
+		case nil:
+		case *tcell.EventKey:
+			if pane != nil {
+				// Other panes can't see local input; any key ends them all.
+				break loop
 			}
-		}
-		// Propagate and cool.
-		for i := 0; i < size; i++ {
-			b0 := buffer[i]
-			b1 := buffer[i+1]
-			b2 := buffer[i+width]
-			b3 := buffer[i+width+1]
-			v := (b0 + b1 + b2 + b3) / 4
-			buffer[i] = v
-			row := i / width
-			col := i % width
-			if row >= height || col >= width {
-				continue
+			if showHelp {
+				// Any key dismisses the help overlay.
+				showHelp = false
+				if ev.Key() != tcell.KeyCtrlC {
+					break
+				}
 			}
-			// Reserve bottom two lines for git info if available.
-			if haveTicker && row >= height-2 {
-				continue
+			var a action
+			var ok bool
+			if *playground {
+				// Bound keys still work; everything else is typing.
+				if a, ok = keys.playgroundLookup(ev); !ok {
+					typing.press(time.Now())
+					break
+				}
+			} else if a, ok = keys.lookup(ev); !ok {
+				break // unbound keys do nothing
 			}
-			var style tcell.Style
-			switch {
-			case v > 15:
-				style = styles[4]
-			case v > 9:
-				style = styles[3]
-			case v > 4:
-				style = styles[2]
+			switch a {
+			case actionExit:
+				break loop
+			case actionStoke, actionIntensityUp:
+				snd.pop()
+				vis.HandleInput(newActionEvent(a))
+			case actionPause:
+				paused = !paused
+			case actionHelp:
+				showHelp = true
+			case actionScreenshot:
+				if path, err := saveScreenshot(s, time.Now()); err != nil {
+					flash = "screenshot failed: " + err.Error()
+				} else {
+					flash = "saved " + path
+				}
+				flashUntil = time.Now().Add(2 * time.Second)
+			case actionThemeCycle:
+				themeIdx = (themeIdx + 1) % len(themes)
+				rotationPos = rotationPosition(rotation, themeIdx)
+				vis = newVis(themeIdx, time.Now().UnixNano())
 			default:
-				style = styles[1]
+				vis.HandleInput(newActionEvent(a))
+			}
+		case *ctlEvent:
+			if ev.cmd.atFrame > frame {
+				// From the --all-panes orchestrator: wait for the frame
+				// every pane applies it on.
+				pendingCtl = append(pendingCtl, ev)
+				break
+			}
+			if applyCtl(ev) {
+				break loop
+			}
+		case *tickerEvent:
+			if ev.err == nil {
+				cards = ev.cards
+				tick = newTicker(tickerCards(cards))
+				if pane != nil {
+					// The cards load at different times in each pane;
+					// line the ticker up with the shared frame.
+					tick.seek((frame + 3) / 4)
+				}
+				haveTicker = len(cards) > 0
+				reserved = 0
+				if haveTicker {
+					reserved = 2
+				}
 			}
-			chIdx := v
-			if chIdx > 9 {
-				chIdx = 9
+			ev.reply <- ev.err
+		case *boardEvent:
+			board = ev.board
+			refreshingBoard = false
+			tick.restyle(tickerCards(cards))
+		case *tcell.EventResize:
+			sw, sh := s.Size()
+			if sw <= 0 || sh <= 0 {
+				break loop
 			}
-			if chIdx < 0 {
-				chIdx = 0
+			if pane != nil {
+				// The canvas stays window-sized; only this pane changed.
+				cache = newFrameCache(sw, sh)
+				cache.originX, cache.originY = pane.x, pane.y
+				s.Clear()
+				break
 			}
-			s.SetContent(col, row, chars[chIdx], nil, style)
+			width, height = sw, sh
+			vis.Resize(width, height)
+			cache = newFrameCache(width, height)
+			s.Clear()
+		case *tcell.EventMouse:
+			// Pop once per click rather than on every drag step.
+			down := ev.Buttons()&tcell.Button1 != 0
+			if down && !mouseDown {
+				snd.pop()
+			}
+			mouseDown = down
+			vis.HandleInput(ev)
+		default:
+			vis.HandleInput(ev)
 		}
 
-		// Draw git info as two aligned lines at bottom.
-		if haveTicker && height >= 2 && len(msgText) > 0 {
-			msgRunes := []rune(msgText)
-			metaRunes := []rune(metaText)
-			msgLen := len(msgRunes)
-			metaLen := len(metaRunes)
-			if msgLen > 0 && metaLen > 0 {
-				for x := 0; x < width; x++ {
-					mi := (tickerOffset + x) % msgLen
-					mj := (tickerOffset + x) % metaLen
-					mr := msgRunes[mi]
-					me := metaRunes[mj]
-					s.SetContent(x, msgRow, mr, nil, tcell.StyleDefault.Foreground(tcell.ColorWhite))
-					s.SetContent(x, metaRow, me, nil, tcell.StyleDefault.Foreground(tcell.ColorWhite))
+		kps := typing.kps(time.Now())
+		if *playground {
+			// Typing speed sets the flame height.
+			if ic, ok := vis.(intensityControl); ok {
+				ic.SetIntensity(typingIntensity(kps))
+				intensity = ic.Intensity()
+			}
+		}
+		// Briefly show the gauge whenever the intensity changes.
+		if ic, ok := vis.(intensityControl); ok && ic.Intensity() != intensity {
+			intensity = ic.Intensity()
+			pickedIntensity = intensity
+			gaugeUntil = time.Now().Add(gaugeDuration)
+		}
+
+		if paused {
+			snd.setLevel(0)
+		} else {
+			snd.setLevel(float64(intensity-minHeat) / (maxHeat - minHeat))
+			// Panes in sync catch up to the shared clock; otherwise
+			// it's one step per frame.
+			target := frame + 1
+			if pane != nil {
+				target = pane.frameAt(time.Now(), frameDelay)
+			}
+			for ; frame < target; frame++ {
+				for len(pendingCtl) > 0 && pendingCtl[0].cmd.atFrame <= frame {
+					ev := pendingCtl[0]
+					pendingCtl = pendingCtl[1:]
+					if applyCtl(ev) {
+						break loop
+					}
 				}
-				if frame%4 == 0 {
-					tickerOffset = (tickerOffset + 1) % msgLen
+				rotateNow := false
+				if *rotate > 0 && frame > 0 {
+					if pane != nil {
+						rotateNow = frame%rotateFrames == 0
+					} else if now := time.Now(); !now.Before(nextRotation) {
+						rotateNow = true
+						nextRotation = now.Add(*rotate)
+					}
 				}
+				if rotateNow {
+					rotationPos = (rotationPos + 1) % len(rotation)
+					themeIdx = rotation[rotationPos]
+					vis = newCrossFade(vis, newVis(themeIdx, seed+int64(frame)), int(fadeDuration/frameDelay))
+				}
+				vis.Step(frame)
+				if burn != nil {
+					burn.advance(frame)
+				}
+				if haveTicker && frame%4 == 0 && tick.advance() && *leaderboard && !refreshingBoard {
+					// Refresh the standings each time round, off the
+					// loop since shortlog can be slow on big repos.
+					refreshingBoard = true
+					go func() {
+						ev := &boardEvent{board: loadLeaderboard(gitDir(), leaderboardSize)}
+						ev.SetEventNow()
+						events <- ev
+					}()
+				}
+			}
+			if f, ok := vis.(*crossFade); ok && f.done() {
+				vis = f.to
 			}
 		}
+		if burn != nil {
+			renderFrame(s, cache, burn.over(vis, height, reserved), width, height, reserved, showHelp)
+		} else {
+			renderFrame(s, cache, vis, width, height, reserved, showHelp)
+		}
+		if haveTicker {
+			tick.draw(s, cache, width, height)
+		}
+		drawLeaderboard(s, cache, width, board)
+		if *playground {
+			drawTypingGauge(s, cache, kps)
+		} else if time.Now().Before(gaugeUntil) {
+			drawGauge(s, cache, intensity)
+		}
+		if time.Now().Before(flashUntil) {
+			// Just above the ticker.
+			drawFlash(s, cache, height-reserved-1, flash)
+		}
+		drawMessage(s, cache, width, height, reserved, away.lines(time.Now()))
+		if showHelp {
+			drawHelp(s, cache, width, height, help)
+		}
+		if paused {
+			drawPaused(s, cache, width)
+			s.Show()
+			continue
+		}
 
 		s.Show()
 		time.Sleep(frameDelay)
-		frame++
+	}
+
+	// Remember the flame height for next time.
+	s.Fini()
+	snd.stop()
+	if pickedIntensity != savedIntensity && !*playground {
+		if err := saveConfigValue("", "intensity", strconv.Itoa(pickedIntensity)); err != nil {
+			log.Printf("saving intensity: %v", err)
+		}
 	}
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// awayMessage is a note for passers-by, shown in a box above the flames.
+type awayMessage struct {
+	text string
+	// since is when the user stepped away; if set, how long ago is shown
+	// under the text.
+	since time.Time
+}
+
+// lines returns what the box shows at now, or nil if there's no message.
+func (m awayMessage) lines(now time.Time) []string {
+	if m.text == "" {
+		return nil
+	}
+	lines := []string{m.text}
+	if !m.since.IsZero() {
+		lines = append(lines, "away for "+awayDuration(now.Sub(m.since)))
+	}
+	return lines
+}
+
+// awayDuration formats d to the minute, e.g. "23m" or "1h05m".
+func awayDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// drawMessage draws lines in a box centered across the screen, a third of
+// the way down the space above the ticker so it sits clear of the flames.
+func drawMessage(s tcell.Screen, cache *frameCache, width, height, reserved int, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	boxW, boxH := boxSize(lines)
+	y0 := (height-reserved)/3 - boxH/2
+	if y0 < 0 {
+		y0 = 0
+	}
+	drawBox(s, cache, (width-boxW)/2, y0, lines, false)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAwayMessageLines(t *testing.T) {
+	start := time.Date(2025, 12, 24, 13, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		msg  awayMessage
+		now  time.Time
+		want []string
+	}{
+		{awayMessage{}, start, nil},
+		{awayMessage{text: "Back at 14:00"}, start.Add(time.Hour), []string{"Back at 14:00"}},
+		{awayMessage{text: "Back at 14:00", since: start}, start.Add(30 * time.Second), []string{"Back at 14:00", "away for <1m"}},
+		{awayMessage{text: "Back at 14:00", since: start}, start.Add(23 * time.Minute), []string{"Back at 14:00", "away for 23m"}},
+		{awayMessage{text: "Lunch", since: start}, start.Add(65 * time.Minute), []string{"Lunch", "away for 1h05m"}},
+	} {
+		if got := tt.msg.lines(tt.now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v at %v: lines = %q, want %q", tt.msg, tt.now.Sub(start), got, tt.want)
+		}
+	}
+}
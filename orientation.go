@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// orientation is the direction the flames travel in.
+type orientation int
+
+const (
+	orientUp orientation = iota
+	orientDown
+	orientLeft
+	orientRight
+)
+
+var orientationNames = []string{"up", "down", "left", "right"}
+
+func parseOrientation(s string) (orientation, error) {
+	for i, name := range orientationNames {
+		if s == name {
+			return orientation(i), nil
+		}
+	}
+	return 0, fmt.Errorf("--orientation: want up, down, left or right, got %q", s)
+}
+
+// orientedVisualization turns a visualization that rises from the bottom
+// edge so that it travels in another direction. The inner visualization
+// keeps running upright, on a canvas turned to match; only coordinates are
+// mapped.
+type orientedVisualization struct {
+	inner         Visualization
+	dir           orientation
+	width, height int
+}
+
+func newOrientedVisualization(inner Visualization, dir orientation) Visualization {
+	if dir == orientUp {
+		return inner
+	}
+	return &orientedVisualization{inner: inner, dir: dir}
+}
+
+// toInner maps screen position x,y to the inner visualization's canvas.
+func (o *orientedVisualization) toInner(x, y int) (int, int) {
+	switch o.dir {
+	case orientDown:
+		return x, o.height - 1 - y
+	case orientLeft:
+		// Sources along the right edge, burning leftwards.
+		return y, x
+	case orientRight:
+		return y, o.width - 1 - x
+	}
+	return x, y
+}
+
+func (o *orientedVisualization) Resize(width, height int) {
+	o.width, o.height = width, height
+	if o.dir == orientLeft || o.dir == orientRight {
+		width, height = height, width
+	}
+	o.inner.Resize(width, height)
+}
+
+func (o *orientedVisualization) Step(frame int) {
+	o.inner.Step(frame)
+}
+
+// pointing turns glyphs that point along the flames.
+var pointing = map[orientation]rune{orientDown: 'v', orientLeft: '<', orientRight: '>'}
+
+func (o *orientedVisualization) Cell(x, y int) (rune, tcell.Style) {
+	ch, style := o.inner.Cell(o.toInner(x, y))
+	if ch == '^' {
+		ch = pointing[o.dir]
+	}
+	return ch, style
+}
+
+func (o *orientedVisualization) HandleInput(ev tcell.Event) bool {
+	if mev, ok := ev.(*tcell.EventMouse); ok {
+		x, y := o.toInner(mev.Position())
+		ev = tcell.NewEventMouse(x, y, mev.Buttons(), mev.Modifiers())
+	}
+	return o.inner.HandleInput(ev)
+}
+
+func (o *orientedVisualization) Intensity() int {
+	if ic, ok := o.inner.(intensityControl); ok {
+		return ic.Intensity()
+	}
+	return defaultIntensity
+}
+
+func (o *orientedVisualization) SetIntensity(v int) {
+	if ic, ok := o.inner.(intensityControl); ok {
+		ic.SetIntensity(v)
+	}
+}
+
+func (o *orientedVisualization) Heat(x, y int) float64 {
+	if hs, ok := o.inner.(heatSource); ok {
+		return hs.Heat(o.toInner(x, y))
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// edgeProbe draws '#' along its bottom row, where the sources would be,
+// and records what it was sized to and clicked at.
+type edgeProbe struct {
+	width, height  int
+	clickX, clickY int
+}
+
+func (p *edgeProbe) Resize(width, height int) { p.width, p.height = width, height }
+func (p *edgeProbe) Step(frame int)           {}
+func (p *edgeProbe) Cell(x, y int) (rune, tcell.Style) {
+	if y == p.height-1 {
+		return '#', tcell.StyleDefault
+	}
+	return '^', tcell.StyleDefault
+}
+func (p *edgeProbe) HandleInput(ev tcell.Event) bool {
+	p.clickX, p.clickY = ev.(*tcell.EventMouse).Position()
+	return true
+}
+
+func TestOrientedVisualization(t *testing.T) {
+	const w, h = 8, 4
+	for _, tc := range []struct {
+		dir          orientation
+		edgeX, edgeY int // a cell on the source edge
+		innerW       int
+		glyph        rune
+	}{
+		{orientDown, 3, 0, w, 'v'},
+		{orientLeft, w - 1, 2, h, '<'},
+		{orientRight, 0, 2, h, '>'},
+	} {
+		probe := &edgeProbe{}
+		o := newOrientedVisualization(probe, tc.dir)
+		o.Resize(w, h)
+		if probe.width != tc.innerW {
+			t.Errorf("%s: inner width = %d, want %d", orientationNames[tc.dir], probe.width, tc.innerW)
+		}
+		if ch, _ := o.Cell(tc.edgeX, tc.edgeY); ch != '#' {
+			t.Errorf("%s: cell %d,%d = %q, want the source edge", orientationNames[tc.dir], tc.edgeX, tc.edgeY, ch)
+		}
+		if ch, _ := o.Cell(w/2, h/2); ch != tc.glyph && ch != '#' {
+			t.Errorf("%s: flame glyph = %q, want %q", orientationNames[tc.dir], ch, tc.glyph)
+		}
+		// A click on the source edge lands on the inner bottom row.
+		o.HandleInput(tcell.NewEventMouse(tc.edgeX, tc.edgeY, tcell.Button1, tcell.ModNone))
+		if probe.clickY != probe.height-1 {
+			t.Errorf("%s: click mapped to row %d, want %d", orientationNames[tc.dir], probe.clickY, probe.height-1)
+		}
+	}
+
+	probe := &edgeProbe{}
+	if newOrientedVisualization(probe, orientUp) != Visualization(probe) {
+		t.Errorf("up should leave the visualization alone")
+	}
+	if _, err := parseOrientation("sideways"); err == nil {
+		t.Errorf("expected an error for an unknown orientation")
+	}
+}
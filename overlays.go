@@ -0,0 +1,221 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// overlay is a layer of glyphs drawn over a visualization, rebuilt every
+// frame. A zero rune means the cell is transparent.
+type overlay struct {
+	width, height int
+	chars         []rune
+	styles        []tcell.Style
+}
+
+func (o *overlay) resize(width, height int) {
+	o.width, o.height = width, height
+	o.chars = make([]rune, width*height)
+	o.styles = make([]tcell.Style, width*height)
+}
+
+func (o *overlay) clear() {
+	for i := range o.chars {
+		o.chars[i] = 0
+	}
+}
+
+// set draws ch at x,y, ignoring positions off the canvas.
+func (o *overlay) set(x, y int, ch rune, style tcell.Style) {
+	if x < 0 || y < 0 || x >= o.width || y >= o.height {
+		return
+	}
+	o.chars[y*o.width+x] = ch
+	o.styles[y*o.width+x] = style
+}
+
+func (o *overlay) at(x, y int) (rune, tcell.Style, bool) {
+	i := y*o.width + x
+	return o.chars[i], o.styles[i], o.chars[i] != 0
+}
+
+// snowVisualization is the fire with snow falling in front of it. Flakes
+// melt when they drift into the flames.
+type snowVisualization struct {
+	*fireVisualization
+	rng    *rand.Rand
+	flakes []flake
+	layer  overlay
+}
+
+type flake struct {
+	x, y, speed float64
+}
+
+var (
+	snowStyle      = tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	snowFaintStyle = tcell.StyleDefault.Foreground(tcell.ColorSilver)
+)
+
+func newSnowVisualization(opts visOptions) *snowVisualization {
+	return &snowVisualization{
+		fireVisualization: newFireVisualization(firePalette(), opts),
+		rng:               rand.New(rand.NewSource(opts.seed)),
+	}
+}
+
+func (s *snowVisualization) Resize(width, height int) {
+	s.fireVisualization.Resize(width, height)
+	s.layer.resize(width, height)
+	s.flakes = make([]flake, width*height/40)
+	for i := range s.flakes {
+		s.flakes[i] = s.newFlake(s.rng.Float64() * float64(height))
+	}
+}
+
+// newFlake returns a flake at height y and a random column.
+func (s *snowVisualization) newFlake(y float64) flake {
+	return flake{x: s.rng.Float64() * float64(s.width), y: y, speed: 0.15 + s.rng.Float64()*0.35}
+}
+
+func (s *snowVisualization) Step(frame int) {
+	s.fireVisualization.Step(frame)
+	s.layer.clear()
+	heat := s.sim.Cells()
+	for i := range s.flakes {
+		f := &s.flakes[i]
+		f.y += f.speed
+		f.x += (s.rng.Float64() - 0.5) * 0.4
+		x, y := int(f.x), int(f.y)
+		if y >= s.height || x < 0 || x >= s.width || heat[y*s.width+x] > 4 {
+			*f = s.newFlake(0)
+			continue
+		}
+		if f.speed > 0.3 {
+			s.layer.set(x, y, '*', snowStyle)
+		} else {
+			s.layer.set(x, y, '·', snowFaintStyle)
+		}
+	}
+}
+
+func (s *snowVisualization) Cell(x, y int) (rune, tcell.Style) {
+	if ch, style, ok := s.layer.at(x, y); ok {
+		return ch, style
+	}
+	return s.fireVisualization.Cell(x, y)
+}
+
+// fireworksVisualization is the fire with rockets bursting above it.
+type fireworksVisualization struct {
+	*fireVisualization
+	rng       *rand.Rand
+	particles []particle
+	layer     overlay
+}
+
+// particle is a rocket on its way up (spark == false) or a spark from its
+// burst.
+type particle struct {
+	x, y, vx, vy float64
+	life         int
+	spark        bool
+	color        tcell.Color
+}
+
+var fireworkColors = []tcell.Color{
+	tcell.NewRGBColor(255, 80, 80),
+	tcell.NewRGBColor(255, 220, 90),
+	tcell.NewRGBColor(110, 200, 255),
+	tcell.NewRGBColor(150, 255, 140),
+	tcell.NewRGBColor(230, 130, 255),
+	tcell.ColorWhite,
+}
+
+// Spark physics, in cells per frame.
+const (
+	sparkGravity = 0.02
+	sparkDrag    = 0.96
+)
+
+func newFireworksVisualization(opts visOptions) *fireworksVisualization {
+	return &fireworksVisualization{
+		fireVisualization: newFireVisualization(firePalette(), opts),
+		rng:               rand.New(rand.NewSource(opts.seed)),
+	}
+}
+
+func (f *fireworksVisualization) Resize(width, height int) {
+	f.fireVisualization.Resize(width, height)
+	f.layer.resize(width, height)
+	f.particles = nil
+}
+
+func (f *fireworksVisualization) Step(frame int) {
+	f.fireVisualization.Step(frame)
+	if f.width >= 8 && f.height > 4 && f.rng.Intn(40) == 0 {
+		// Launch from the fire, bursting somewhere in the top half.
+		f.particles = append(f.particles, particle{
+			x:     float64(f.width/8 + f.rng.Intn(f.width-f.width/4)),
+			y:     float64(f.height - 1),
+			vy:    -0.8,
+			life:  f.height/2 + f.rng.Intn(f.height/3+1),
+			color: fireworkColors[f.rng.Intn(len(fireworkColors))],
+		})
+	}
+
+	f.layer.clear()
+	live := f.particles[:0]
+	var bursts []particle
+	for _, p := range f.particles {
+		p.life--
+		if p.life <= 0 {
+			if !p.spark {
+				bursts = append(bursts, p)
+			}
+			continue
+		}
+		p.x += p.vx
+		p.y += p.vy
+		if p.spark {
+			p.vx *= sparkDrag
+			p.vy = p.vy*sparkDrag + sparkGravity
+		}
+		live = append(live, p)
+		style := tcell.StyleDefault.Foreground(p.color)
+		switch {
+		case !p.spark:
+			f.layer.set(int(p.x), int(p.y), '|', style.Foreground(tcell.ColorSilver))
+		case p.life > 12:
+			f.layer.set(int(p.x), int(p.y), '*', style.Bold(true))
+		default:
+			f.layer.set(int(p.x), int(p.y), '.', style)
+		}
+	}
+	f.particles = live
+	for _, b := range bursts {
+		n := 16 + f.rng.Intn(12)
+		for i := 0; i < n; i++ {
+			angle := 2 * math.Pi * float64(i) / float64(n)
+			speed := 0.5 + f.rng.Float64()*0.5
+			f.particles = append(f.particles, particle{
+				x: b.x, y: b.y,
+				// Cells are about twice as tall as they are wide.
+				vx:    math.Cos(angle) * speed,
+				vy:    math.Sin(angle) * speed / 2,
+				life:  20 + f.rng.Intn(15),
+				spark: true,
+				color: b.color,
+			})
+		}
+	}
+}
+
+func (f *fireworksVisualization) Cell(x, y int) (rune, tcell.Style) {
+	if ch, style, ok := f.layer.at(x, y); ok {
+		return ch, style
+	}
+	return f.fireVisualization.Cell(x, y)
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// --all-panes turns every pane of the current tmux window into part of one
+// big fire. Each pane is swapped out for a renderer started in a hidden
+// window; every renderer runs the same window-sized simulation from a
+// shared seed, stepped by the clock from a shared start time, and draws
+// only its own pane's piece. Panes therefore stay in sync without talking
+// to each other. The first renderer to see a key press (or to fail)
+// signals a tmux wait-for channel, and the original panes are swapped
+// back. The orchestrator also watches for renderers that die without
+// signalling.
+//
+// The orchestrator owns the control socket. It handles dismiss itself and
+// passes every other command on to each renderer's private socket, stamped
+// with a frame a little ahead so they all apply it on the same one.
+
+// paneSync is what a renderer launched by --all-panes needs to know.
+type paneSync struct {
+	// The pane's position within the window-sized canvas.
+	x, y                      int
+	canvasWidth, canvasHeight int
+
+	seed    int64
+	start   time.Time
+	channel string // tmux wait-for channel signalled on exit
+}
+
+// parsePaneSync parses the hidden --pane-view and --sync flags:
+// "x,y,canvasW,canvasH" and "seed,startUnixMillis,channel".
+func parsePaneSync(view, sync string) (*paneSync, error) {
+	v := strings.Split(view, ",")
+	if len(v) != 4 {
+		return nil, fmt.Errorf("--pane-view: want x,y,canvasW,canvasH, got %q", view)
+	}
+	nums := make([]int, len(v))
+	for i, f := range v {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("--pane-view: %w", err)
+		}
+		nums[i] = n
+	}
+	parts := strings.SplitN(sync, ",", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("--sync: want seed,start,channel, got %q", sync)
+	}
+	seed, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("--sync: %w", err)
+	}
+	startMs, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("--sync: %w", err)
+	}
+	return &paneSync{
+		x: nums[0], y: nums[1],
+		canvasWidth: nums[2], canvasHeight: nums[3],
+		seed:    seed,
+		start:   time.UnixMilli(startMs),
+		channel: parts[2],
+	}, nil
+}
+
+// frameAt returns the frame every pane should be showing at now.
+func (p *paneSync) frameAt(now time.Time, frameDelay time.Duration) int {
+	if now.Before(p.start) {
+		return 0
+	}
+	return int(now.Sub(p.start) / frameDelay)
+}
+
+// finish tells the orchestrator this pane is done, then waits to be killed
+// so the pane doesn't close (and reflow the layout) before the original is
+// swapped back in.
+func (p *paneSync) finish() {
+	exec.Command("tmux", "wait-for", "-S", p.channel).Run()
+	time.Sleep(5 * time.Second)
+}
+
+// tmuxPane is a pane of the current window.
+type tmuxPane struct {
+	id        string
+	left, top int
+	// renderer is the pane running this pane's piece of the fire, and
+	// swapped reports whether it currently sits in this pane's place.
+	renderer string
+	swapped  bool
+	// socket is where the renderer listens for control commands.
+	socket string
+}
+
+// paneCtlLead is how far ahead the orchestrator schedules a control
+// command, so every renderer has it before the frame comes round.
+const paneCtlLead = 300 * time.Millisecond
+
+// runTmux runs a tmux command and returns its trimmed output.
+func runTmux(args ...string) (string, error) {
+	out, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("tmux %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("tmux %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runAllPanes implements --all-panes. forward holds the flags to pass on to
+// each renderer.
+func runAllPanes(forward []string) error {
+	if runtime.GOOS == "windows" {
+		return errors.New("--all-panes needs tmux, which isn't available on Windows")
+	}
+	if os.Getenv("TMUX") == "" {
+		return errors.New("--all-panes must be run inside tmux")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	size, err := runTmux("display-message", "-p", "#{window_width} #{window_height}")
+	if err != nil {
+		return err
+	}
+	var canvasW, canvasH int
+	if _, err := fmt.Sscan(size, &canvasW, &canvasH); err != nil {
+		return fmt.Errorf("reading window size %q: %w", size, err)
+	}
+	list, err := runTmux("list-panes", "-F", "#{pane_id} #{pane_left} #{pane_top}")
+	if err != nil {
+		return err
+	}
+	var panes []*tmuxPane
+	for _, line := range strings.Split(list, "\n") {
+		p := &tmuxPane{}
+		if _, err := fmt.Sscan(line, &p.id, &p.left, &p.top); err != nil {
+			return fmt.Errorf("reading pane %q: %w", line, err)
+		}
+		panes = append(panes, p)
+	}
+
+	channel := fmt.Sprintf("yule-log-%d", os.Getpid())
+	seed := time.Now().UnixNano()
+	// Give every renderer time to start before the first frame.
+	start := time.Now().Add(time.Second).UnixMilli()
+	sync := fmt.Sprintf("%d,%d,%s", seed, start, channel)
+
+	defer restorePanes(panes)
+
+	socketDir, err := os.MkdirTemp("", "yule-log-panes")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(socketDir)
+	// Best-effort, as for a single fire.
+	ctl := make(chan tcell.Event)
+	if ln, err := listenControl(ctl); err == nil {
+		defer ln.Close()
+	}
+	clock := &paneSync{start: time.UnixMilli(start)}
+
+	// Restore the layout if we're told to stop.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, stopSignals...)
+	defer signal.Stop(sigs)
+	woken := make(chan error, 1)
+
+	for i, p := range panes {
+		p.socket = filepath.Join(socketDir, fmt.Sprintf("pane-%d.sock", i))
+		view := fmt.Sprintf("%d,%d,%d,%d", p.left, p.top, canvasW, canvasH)
+		argv := append([]string{exe, "--pane-view=" + view, "--sync=" + sync}, forward...)
+		cmd := shellJoin(append([]string{"env", "YULE_LOG_GIT_DIR=" + gitDir(), "YULE_LOG_SOCKET=" + p.socket}, argv...))
+		id, err := runTmux("new-window", "-d", "-P", "-F", "#{pane_id}", cmd)
+		if err != nil {
+			return err
+		}
+		p.renderer = id
+		// Keep a renderer's pane in place if it dies, so the original
+		// can still be swapped back (pane options need tmux 3.0).
+		runTmux("set-option", "-p", "-t", p.renderer, "remain-on-exit", "on")
+		if _, err := runTmux("swap-pane", "-d", "-s", p.renderer, "-t", p.id); err != nil {
+			return err
+		}
+		p.swapped = true
+	}
+
+	go func() {
+		_, err := runTmux("wait-for", channel)
+		woken <- err
+	}()
+	// Renderers signal the channel when they exit, but one that crashes
+	// can't; check on them too.
+	poll := time.NewTicker(time.Second)
+	defer poll.Stop()
+	for {
+		select {
+		case err := <-woken:
+			return err
+		case <-sigs:
+			return nil
+		case e := <-ctl:
+			ev := e.(*ctlEvent)
+			if ev.cmd.verb == "dismiss" {
+				ev.reply <- nil
+				return nil
+			}
+			cmd := ev.cmd
+			cmd.atFrame = clock.frameAt(time.Now().Add(paneCtlLead), frameDelay)
+			go func() { ev.reply <- forwardControl(panes, cmd) }()
+		case <-poll.C:
+			if !renderersAlive(panes) {
+				return nil
+			}
+		}
+	}
+}
+
+// forwardControl sends cmd to every renderer and returns the first error
+// any of them replies with.
+func forwardControl(panes []*tmuxPane, cmd ctlCommand) error {
+	errs := make(chan error, len(panes))
+	for _, p := range panes {
+		go func(socket string) { errs <- sendControl(socket, cmd) }(p.socket)
+	}
+	var first error
+	for range panes {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// renderersAlive reports whether every renderer is still running.
+func renderersAlive(panes []*tmuxPane) bool {
+	for _, p := range panes {
+		dead, err := runTmux("display-message", "-p", "-t", p.renderer, "#{pane_dead}")
+		if err != nil || dead == "1" {
+			return false
+		}
+	}
+	return true
+}
+
+// restorePanes swaps the original panes back and closes the renderers.
+func restorePanes(panes []*tmuxPane) {
+	for _, p := range panes {
+		if p.swapped {
+			runTmux("swap-pane", "-d", "-s", p.renderer, "-t", p.id)
+		}
+	}
+	for _, p := range panes {
+		if p.renderer != "" {
+			runTmux("kill-pane", "-t", p.renderer)
+		}
+	}
+}
+
+// forwardedFlags returns the flags set on fs, except those in skip, in a
+// form that can be passed on to another invocation.
+func forwardedFlags(fs *flag.FlagSet, skip ...string) []string {
+	var out []string
+	fs.Visit(func(f *flag.Flag) {
+		for _, name := range skip {
+			if f.Name == name {
+				return
+			}
+		}
+		out = append(out, "--"+f.Name+"="+f.Value.String())
+	})
+	return out
+}
+
+// gitDir is the directory the ticker reads git history from.
+func gitDir() string {
+	if dir := os.Getenv("YULE_LOG_GIT_DIR"); dir != "" {
+		return dir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// shellJoin quotes args for the shell tmux runs commands with.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParsePaneSync(t *testing.T) {
+	p, err := parsePaneSync("51,0,100,30", "42,1700000000000,yule-log-7")
+	if err != nil {
+		t.Fatalf("parsePaneSync: %v", err)
+	}
+	if p.x != 51 || p.y != 0 || p.canvasWidth != 100 || p.canvasHeight != 30 {
+		t.Fatalf("unexpected view %+v", p)
+	}
+	if p.seed != 42 || p.channel != "yule-log-7" || !p.start.Equal(time.UnixMilli(1700000000000)) {
+		t.Fatalf("unexpected sync %+v", p)
+	}
+
+	frameDelay := 30 * time.Millisecond
+	if got := p.frameAt(p.start.Add(-time.Second), frameDelay); got != 0 {
+		t.Fatalf("frameAt before start = %d, want 0", got)
+	}
+	if got := p.frameAt(p.start.Add(95*time.Millisecond), frameDelay); got != 3 {
+		t.Fatalf("frameAt = %d, want 3", got)
+	}
+
+	for _, bad := range [][2]string{{"1,2,3", "1,2,c"}, {"1,2,3,x", "1,2,c"}, {"1,2,3,4", "1,c"}} {
+		if _, err := parsePaneSync(bad[0], bad[1]); err == nil {
+			t.Fatalf("expected error for %q %q", bad[0], bad[1])
+		}
+	}
+}
+
+func TestForwardedFlagsAndShellJoin(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("contribs", false, "")
+	fs.Bool("all-panes", false, "")
+	fs.String("cycle-key", "", "")
+	if err := fs.Parse([]string{"--all-panes", "--contribs", "--cycle-key", "it's"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got := forwardedFlags(fs, "all-panes")
+	want := []string{"--contribs=true", "--cycle-key=it's"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("forwardedFlags = %q, want %q", got, want)
+	}
+	if got, want := shellJoin(got), `'--contribs=true' '--cycle-key=it'\''s'`; got != want {
+		t.Fatalf("shellJoin = %s, want %s", got, want)
+	}
+}
+
+func TestForwardControl(t *testing.T) {
+	// Two renderers, each on its own socket; the second rejects themes.
+	var panes []*tmuxPane
+	got := make(chan ctlCommand, 2)
+	for i := 0; i < 2; i++ {
+		socket := filepath.Join(t.TempDir(), "pane.sock")
+		t.Setenv("YULE_LOG_SOCKET", socket)
+		events := make(chan tcell.Event)
+		ln, err := listenControl(events)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+		go func(reject bool) {
+			for e := range events {
+				ev := e.(*ctlEvent)
+				got <- ev.cmd
+				if reject && ev.cmd.verb == "set-theme" {
+					ev.reply <- errors.New("unknown theme")
+				} else {
+					ev.reply <- nil
+				}
+			}
+		}(i == 1)
+		panes = append(panes, &tmuxPane{socket: socket})
+	}
+
+	cmd := ctlCommand{verb: "show-message", arg: "back soon", atFrame: 40}
+	if err := forwardControl(panes, cmd); err != nil {
+		t.Fatalf("forwardControl: %v", err)
+	}
+	for range panes {
+		if c := <-got; c != cmd {
+			t.Errorf("renderer got %+v, want %+v", c, cmd)
+		}
+	}
+	if err := forwardControl(panes, ctlCommand{verb: "set-theme", arg: "matrix"}); err == nil || err.Error() != "unknown theme" {
+		t.Errorf("forwardControl = %v, want the renderer's error", err)
+	}
+	<-got
+	<-got
+}
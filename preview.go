@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// tile is a rectangle of the preview screen.
+type tile struct {
+	x, y, width, height int
+}
+
+// previewTiles splits a width×height screen into a grid of n tiles, as
+// square as it can. It also returns the number of columns.
+func previewTiles(n, width, height int) ([]tile, int) {
+	cols := 1
+	for cols*cols < n {
+		cols++
+	}
+	rows := (n + cols - 1) / cols
+	tiles := make([]tile, n)
+	for i := range tiles {
+		c, r := i%cols, i/cols
+		x0, x1 := width*c/cols, width*(c+1)/cols
+		y0, y1 := height*r/rows, height*(r+1)/rows
+		tiles[i] = tile{x: x0, y: y0, width: x1 - x0, height: y1 - y0}
+	}
+	return tiles, cols
+}
+
+// runPreview implements `yule-log preview`: show every theme side by side
+// and save the one picked as the default.
+func runPreview(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := s.Init(); err != nil {
+		return err
+	}
+	name, ok := previewLoop(s)
+	s.Fini()
+	if !ok {
+		return nil
+	}
+	if err := saveConfigValue("", "theme", strconv.Quote(name)); err != nil {
+		return err
+	}
+	path, _ := configPath()
+	fmt.Fprintf(w, "Theme set to %s in %s\n", name, path)
+	return nil
+}
+
+// previewLoop runs the preview until a theme is picked with Enter (ok is
+// true) or the user quits.
+func previewLoop(s tcell.Screen) (name string, ok bool) {
+	s.HideCursor()
+	colors := screenColorMode(s)
+	vis := make([]Visualization, len(themes))
+	for i, th := range themes {
+		vis[i] = th.newVisualization(visOptions{seed: time.Now().UnixNano() + int64(i), colors: colors, layout: th.layout})
+	}
+	selected := 0
+	if cfg, err := loadConfig(); err == nil {
+		if v, found := cfg.get("", "theme"); found {
+			if i, found := themeIndex(v); found {
+				selected = i
+			}
+		}
+	}
+
+	var tiles []tile
+	var cols int
+	var cache *frameCache
+	layout := func() {
+		width, height := s.Size()
+		tiles, cols = previewTiles(len(themes), width, height)
+		cache = newFrameCache(width, height)
+		for i, t := range tiles {
+			// The bottom row of each tile is its label.
+			vis[i].Resize(t.width, max(t.height-1, 0))
+		}
+		s.Clear()
+	}
+	layout()
+
+	events := make(chan tcell.Event, 10)
+	quit := make(chan struct{})
+	defer close(quit)
+	go s.ChannelEvents(events, quit)
+
+	for frame := 0; ; frame++ {
+		select {
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *tcell.EventKey:
+				switch ev.Key() {
+				case tcell.KeyEscape, tcell.KeyCtrlC:
+					return "", false
+				case tcell.KeyEnter:
+					return themes[selected].name, true
+				case tcell.KeyLeft:
+					if selected%cols > 0 {
+						selected--
+					}
+				case tcell.KeyRight:
+					if selected%cols < cols-1 && selected+1 < len(themes) {
+						selected++
+					}
+				case tcell.KeyUp:
+					if selected >= cols {
+						selected -= cols
+					}
+				case tcell.KeyDown:
+					if selected+cols < len(themes) {
+						selected += cols
+					}
+				case tcell.KeyRune:
+					if ev.Rune() == 'q' {
+						return "", false
+					}
+				}
+			case *tcell.EventResize:
+				layout()
+			}
+		default:
+		}
+
+		for i, t := range tiles {
+			vis[i].Step(frame)
+			drawTile(s, cache, t, vis[i], themes[i].name, i == selected)
+		}
+		s.Show()
+		time.Sleep(30 * time.Millisecond)
+	}
+}
+
+// drawTile draws vis into t with the theme's name along the bottom row,
+// highlighted if it's the selected theme.
+func drawTile(s tcell.Screen, cache *frameCache, t tile, vis Visualization, name string, selected bool) {
+	if t.width <= 0 || t.height <= 1 {
+		return
+	}
+	for y := 0; y < t.height-1; y++ {
+		for x := 0; x < t.width; x++ {
+			ch, style := vis.Cell(x, y)
+			cache.set(s, t.x+x, t.y+y, ch, style)
+		}
+	}
+	labelStyle := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	label := "  " + name + "  "
+	if selected {
+		labelStyle = tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite).Bold(true)
+		label = "▶ " + name + " ◀"
+	}
+	runes := []rune(truncate(label, t.width))
+	start := (t.width - len(runes)) / 2
+	for x := 0; x < t.width; x++ {
+		ch, style := ' ', tcell.StyleDefault
+		if i := x - start; i >= 0 && i < len(runes) {
+			ch, style = runes[i], labelStyle
+		}
+		cache.set(s, t.x+x, t.y+t.height-1, ch, style)
+	}
+}
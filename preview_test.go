@@ -0,0 +1,42 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPreviewTiles(t *testing.T) {
+	tiles, cols := previewTiles(5, 90, 30)
+	if len(tiles) != 5 || cols != 3 {
+		t.Fatalf("got %d tiles in %d columns, want 5 in 3", len(tiles), cols)
+	}
+	if tiles[0] != (tile{0, 0, 30, 15}) || tiles[4] != (tile{30, 15, 30, 15}) {
+		t.Fatalf("tiles = %+v", tiles)
+	}
+}
+
+func TestPreviewLoop_SelectsWithArrowsAndEnter(t *testing.T) {
+	t.Setenv("YULE_LOG_CONFIG", filepath.Join(t.TempDir(), "config"))
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer sim.Fini()
+	sim.SetSize(90, 30)
+
+	// Right, then down: the tile below the second one.
+	sim.InjectKey(tcell.KeyRight, 0, tcell.ModNone)
+	sim.InjectKey(tcell.KeyDown, 0, tcell.ModNone)
+	sim.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	name, ok := previewLoop(sim)
+	if !ok || name != themes[4].name {
+		t.Fatalf("picked %q, %v; want %q", name, ok, themes[4].name)
+	}
+
+	sim.InjectKey(tcell.KeyEscape, 0, tcell.ModNone)
+	if _, ok := previewLoop(sim); ok {
+		t.Fatalf("expected Esc to quit without picking")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// latestReleaseStyle highlights the newest release in the ticker.
+var latestReleaseStyle = tcell.StyleDefault.Foreground(tcell.ColorGold).Bold(true)
+
+// release is a GitHub release or, failing that, a git tag.
+type release struct {
+	tag, name string
+	when      time.Time
+	published bool // a GitHub release rather than a bare tag
+}
+
+// releaseCards returns ticker cards for the n most recent releases of the
+// repository in dir: from GitHub via gh if it can, otherwise from its git
+// tags. The newest is highlighted.
+func releaseCards(dir string, n int) []tickerCard {
+	rels := ghReleases(dir, n)
+	if len(rels) == 0 {
+		rels = gitTags(dir, n)
+	}
+	return releasesToCards(rels, time.Now())
+}
+
+// ghReleases lists releases with `gh api`, newest first. It returns nil if
+// gh isn't installed, isn't logged in or the repository isn't on GitHub.
+func ghReleases(dir string, n int) []release {
+	// Don't hold up startup for long on a slow network.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gh", "api", "repos/{owner}/{repo}/releases?per_page="+strconv.Itoa(n),
+		"--jq", `.[] | select(.draft | not) | [.tag_name, .name, .published_at] | @tsv`)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var rels []release
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 {
+			continue
+		}
+		when, _ := time.Parse(time.RFC3339, parts[2])
+		rels = append(rels, release{tag: parts[0], name: parts[1], when: when, published: true})
+	}
+	return rels
+}
+
+// gitTags lists the n most recently created tags.
+func gitTags(dir string, n int) []release {
+	cmd := exec.Command("git", "tag", "--sort=-creatordate", "--format=%(refname:short)%09%(creatordate:unix)")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parseTagList(string(out), n)
+}
+
+// parseTagList parses "tag<TAB>unix time" lines, keeping at most n.
+func parseTagList(out string, n int) []release {
+	var rels []release
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		tag, at, ok := strings.Cut(line, "\t")
+		if !ok || tag == "" {
+			continue
+		}
+		secs, _ := strconv.ParseInt(at, 10, 64)
+		rels = append(rels, release{tag: tag, when: time.Unix(secs, 0)})
+		if len(rels) == n {
+			break
+		}
+	}
+	return rels
+}
+
+// releasesToCards turns releases, newest first, into ticker cards.
+func releasesToCards(rels []release, now time.Time) []tickerCard {
+	var cards []tickerCard
+	for i, r := range rels {
+		msg := r.tag
+		if r.name != "" && r.name != r.tag {
+			msg += " — " + r.name
+		}
+		meta := "tagged " + relativeAge(r.when, now)
+		if r.published {
+			meta = "released " + relativeAge(r.when, now)
+		}
+		card := tickerCard{msg: msg, meta: meta, when: r.when}
+		if i == 0 {
+			card.msg = "★ latest: " + card.msg
+			card.style = latestReleaseStyle
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
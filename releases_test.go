@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseTagList(t *testing.T) {
+	rels := parseTagList("v1.2.0\t1700000000\nv1.1.0\t1690000000\nv1.0.0\t1680000000\n", 2)
+	if len(rels) != 2 || rels[0].tag != "v1.2.0" || rels[1].when.Unix() != 1690000000 {
+		t.Fatalf("got %+v", rels)
+	}
+}
+
+func TestReleasesToCards(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	cards := releasesToCards([]release{
+		{tag: "v2.0.0", name: "Solstice", when: now.Add(-48 * time.Hour), published: true},
+		{tag: "v1.9.0", when: now.Add(-21 * 24 * time.Hour)},
+	}, now)
+	if len(cards) != 2 {
+		t.Fatalf("got %d cards", len(cards))
+	}
+	if cards[0].msg != "★ latest: v2.0.0 — Solstice" || cards[0].meta != "released 2 days ago" || cards[0].style != latestReleaseStyle {
+		t.Errorf("latest card = %+v", cards[0])
+	}
+	if cards[1].msg != "v1.9.0" || cards[1].meta != "tagged 3 weeks ago" || cards[1].style != (tcell.Style{}) {
+		t.Errorf("older card = %+v", cards[1])
+	}
+}
+
+func TestTicker_CardStyles(t *testing.T) {
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer sim.Fini()
+	sim.SetSize(40, 2)
+
+	tick := newTicker([]tickerCard{
+		{msg: "v1.0.0", meta: "tagged today", style: latestReleaseStyle},
+		{msg: "Fix bug", meta: "by Ada"},
+	})
+	tick.draw(sim, newFrameCache(40, 2), 40, 2)
+	if _, _, style, _ := sim.GetContent(0, 0); style != latestReleaseStyle {
+		t.Errorf("first card should be highlighted")
+	}
+	second := strings.Index(string(tick.msg), "Fix bug")
+	if _, _, style, _ := sim.GetContent(second, 1); style != tick.style {
+		t.Errorf("second card should use the ticker style")
+	}
+}
+
+func TestLoadTickerCards_Errors(t *testing.T) {
+	if _, err := loadTickerCards("blame", ""); err == nil {
+		t.Errorf("expected blame without a file to fail")
+	}
+	if _, err := loadTickerCards("commits+gossip", ""); err == nil {
+		t.Errorf("expected an unknown source to fail")
+	}
+}
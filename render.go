@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// cell is a single glyph and style as drawn to the screen.
+type cell struct {
+	ch    rune
+	style tcell.Style
+}
+
+// frameCache remembers what was last drawn to every cell so each frame only
+// touches the screen where something actually changed. On large terminals
+// (and over SSH) repainting every cell every frame is expensive.
+//
+// Drawing happens in canvas coordinates. The canvas is normally the whole
+// screen, but with --all-panes each pane shows its own piece of a
+// window-sized canvas, so the screen starts at originX,originY.
+type frameCache struct {
+	width, height    int
+	originX, originY int
+	cells            []cell
+}
+
+func newFrameCache(width, height int) *frameCache {
+	return &frameCache{
+		width:  width,
+		height: height,
+		cells:  make([]cell, width*height),
+	}
+}
+
+// set draws ch at canvas position x,y with style unless the cell already
+// holds exactly that. Positions off screen are ignored.
+func (c *frameCache) set(s tcell.Screen, x, y int, ch rune, style tcell.Style) {
+	x, y = x-c.originX, y-c.originY
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return
+	}
+	i := y*c.width + x
+	if c.cells[i].ch == ch && c.cells[i].style == style {
+		return
+	}
+	c.cells[i] = cell{ch: ch, style: style}
+	s.SetContent(x, y, ch, nil, style)
+}
+
+// renderFrame draws the active visualization, skipping the bottom
+// reservedRows rows (used by the ticker). dim fades it behind an overlay.
+func renderFrame(s tcell.Screen, cache *frameCache, vis Visualization, width, height, reservedRows int, dim bool) {
+	// Only visit the part of the canvas that is on screen.
+	rowEnd := min(height-reservedRows, cache.originY+cache.height)
+	colEnd := min(width, cache.originX+cache.width)
+	for row := max(0, cache.originY); row < rowEnd; row++ {
+		for col := max(0, cache.originX); col < colEnd; col++ {
+			ch, style := vis.Cell(col, row)
+			if dim {
+				style = style.Dim(true)
+			}
+			cache.set(s, col, row, ch, style)
+		}
+	}
+}
+
+// drawPaused shows a dim "paused" badge in the top-right corner.
+func drawPaused(s tcell.Screen, cache *frameCache, width int) {
+	const label = " paused "
+	style := tcell.StyleDefault.Foreground(tcell.ColorGray).Dim(true)
+	x := width - len(label) - 1
+	for i, ch := range label {
+		cache.set(s, x+i, 0, ch, style)
+	}
+}
+
+// drawGauge shows the current intensity as a bar in the top-left corner.
+func drawGauge(s tcell.Screen, cache *frameCache, intensity int) {
+	const steps = (maxHeat - minHeat) / intensityStep
+	filled := (intensity - minHeat) / intensityStep
+	label := "flame " + strings.Repeat("■", filled) + strings.Repeat("□", steps-filled) + " " + strconv.Itoa(intensity)
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	for i, ch := range []rune(" " + label + " ") {
+		cache.set(s, 1+i, 0, ch, style)
+	}
+}
+
+// drawFlash shows a short status message at the start of row y.
+func drawFlash(s tcell.Screen, cache *frameCache, y int, msg string) {
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	for i, ch := range []rune(" " + msg + " ") {
+		cache.set(s, i, y, ch, style)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// countingScreen counts SetContent calls on top of a simulation screen.
+type countingScreen struct {
+	tcell.SimulationScreen
+	sets int
+}
+
+func (c *countingScreen) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	c.sets++
+	c.SimulationScreen.SetContent(x, y, mainc, combc, style)
+}
+
+func TestFrameCache_SkipsUnchangedCells(t *testing.T) {
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer sim.Fini()
+	s := &countingScreen{SimulationScreen: sim}
+
+	cache := newFrameCache(4, 2)
+	style := tcell.StyleDefault.Foreground(tcell.ColorRed)
+	cache.set(s, 1, 1, '#', style)
+	cache.set(s, 1, 1, '#', style)
+	if s.sets != 1 {
+		t.Fatalf("expected 1 SetContent call for repeated cell, got %d", s.sets)
+	}
+	cache.set(s, 1, 1, '#', style.Bold(true))
+	cache.set(s, 1, 1, '*', style.Bold(true))
+	if s.sets != 3 {
+		t.Fatalf("expected changed style/glyph to be drawn, got %d calls", s.sets)
+	}
+	cache.set(s, 4, 0, '#', style)
+	cache.set(s, -1, 0, '#', style)
+	if s.sets != 3 {
+		t.Fatalf("expected out-of-range cells to be ignored, got %d calls", s.sets)
+	}
+}
+
+func TestFrameCache_Origin(t *testing.T) {
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer sim.Fini()
+	sim.SetSize(4, 2)
+
+	cache := newFrameCache(4, 2)
+	cache.originX, cache.originY = 10, 5
+	cache.set(sim, 11, 6, '#', tcell.StyleDefault)
+	cache.set(sim, 1, 1, '*', tcell.StyleDefault)
+	sim.Show()
+	if ch, _, _, _ := sim.GetContent(1, 1); ch != '#' {
+		t.Fatalf("canvas 11,6 should land on screen 1,1, got %q", ch)
+	}
+}
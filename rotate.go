@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// fadeDuration is how long --rotate takes to blend one theme into the
+// next.
+const fadeDuration = time.Second
+
+// checkRotateInterval rejects a --rotate interval too short to finish
+// the cross-fade before the next switch. Zero means no rotation.
+func checkRotateInterval(d time.Duration) error {
+	if d != 0 && d < fadeDuration {
+		return fmt.Errorf("--rotate %v is shorter than the %v cross-fade between themes", d, fadeDuration)
+	}
+	return nil
+}
+
+// rotationThemes returns the themes --rotate cycles through, as indexes
+// into themes: the config's rotate list, or every theme.
+func rotationThemes(cfg config) ([]int, error) {
+	v, ok := cfg.get("", "rotate")
+	if !ok {
+		all := make([]int, len(themes))
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+	var out []int
+	for _, name := range configList(v) {
+		i, ok := themeIndex(name)
+		if !ok {
+			return nil, fmt.Errorf("rotate: unknown theme %q", name)
+		}
+		out = append(out, i)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("rotate: no themes listed")
+	}
+	return out, nil
+}
+
+// rotationPosition returns where theme idx sits in rotation, or -1 if it
+// isn't in it, so the next rotation starts from the beginning.
+func rotationPosition(rotation []int, idx int) int {
+	for i, r := range rotation {
+		if r == idx {
+			return i
+		}
+	}
+	return -1
+}
+
+// crossFade is a Visualization that blends one visualization into another
+// over a number of frames. Both keep running underneath; input goes to the
+// incoming one.
+type crossFade struct {
+	from, to    Visualization
+	step, steps int
+}
+
+func newCrossFade(from, to Visualization, steps int) *crossFade {
+	return &crossFade{from: from, to: to, steps: max(steps, 1)}
+}
+
+// done reports whether the fade has finished, after which to can be used
+// on its own.
+func (c *crossFade) done() bool {
+	return c.step >= c.steps
+}
+
+func (c *crossFade) Resize(width, height int) {
+	c.from.Resize(width, height)
+	c.to.Resize(width, height)
+}
+
+func (c *crossFade) Step(frame int) {
+	c.from.Step(frame)
+	c.to.Step(frame)
+	if c.step < c.steps {
+		c.step++
+	}
+}
+
+func (c *crossFade) Cell(x, y int) (rune, tcell.Style) {
+	t := float64(c.step) / float64(c.steps)
+	fromCh, fromStyle := c.from.Cell(x, y)
+	toCh, toStyle := c.to.Cell(x, y)
+	ch := fromCh
+	if t >= 0.5 {
+		ch = toCh
+	}
+	return ch, blendStyles(fromStyle, toStyle, t)
+}
+
+func (c *crossFade) HandleInput(ev tcell.Event) bool {
+	return c.to.HandleInput(ev)
+}
+
+// Intensity and SetIntensity go to the incoming visualization, so the
+// keys, --playground and ctl keep working during a fade.
+func (c *crossFade) Intensity() int {
+	if ic, ok := c.to.(intensityControl); ok {
+		return ic.Intensity()
+	}
+	return defaultIntensity
+}
+
+func (c *crossFade) SetIntensity(v int) {
+	if ic, ok := c.to.(intensityControl); ok {
+		ic.SetIntensity(v)
+	}
+}
+
+// Heat is that of the incoming visualization, if it has any.
+func (c *crossFade) Heat(x, y int) float64 {
+	if hs, ok := c.to.(heatSource); ok {
+		return hs.Heat(x, y)
+	}
+	return 0
+}
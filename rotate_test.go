@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRotationThemes(t *testing.T) {
+	all, err := rotationThemes(config{})
+	if err != nil || len(all) != len(themes) {
+		t.Fatalf("default rotation = %v, %v; want every theme", all, err)
+	}
+
+	cfg, err := parseConfig(strings.NewReader(`rotate = ["contribs", "fire"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := rotationThemes(cfg)
+	contribs, _ := themeIndex("contribs")
+	fire, _ := themeIndex("fire")
+	if err != nil || len(got) != 2 || got[0] != contribs || got[1] != fire {
+		t.Fatalf("rotation = %v, %v", got, err)
+	}
+
+	cfg, _ = parseConfig(strings.NewReader(`rotate = ["fire", "lava"]`))
+	if _, err := rotationThemes(cfg); err == nil {
+		t.Fatalf("expected an error for an unknown theme")
+	}
+}
+
+func TestCheckRotateInterval(t *testing.T) {
+	for d, ok := range map[time.Duration]bool{
+		0:                      true,
+		10 * time.Minute:       true,
+		fadeDuration:           true,
+		10 * time.Millisecond:  false,
+		500 * time.Millisecond: false,
+		-time.Minute:           false,
+	} {
+		if err := checkRotateInterval(d); (err == nil) != ok {
+			t.Errorf("checkRotateInterval(%v) = %v", d, err)
+		}
+	}
+}
+
+func TestRotationPosition(t *testing.T) {
+	rotation := []int{3, 1, 4}
+	if got := rotationPosition(rotation, 4); got != 2 {
+		t.Errorf("position of 4 = %d, want 2", got)
+	}
+	if got := rotationPosition(rotation, 0); got != -1 {
+		t.Errorf("position of a theme outside the rotation = %d, want -1", got)
+	}
+}
+
+// solid is a Visualization that fills the screen with one glyph and color.
+type solid struct {
+	ch    rune
+	style tcell.Style
+	steps int
+}
+
+func (s *solid) Resize(width, height int)          {}
+func (s *solid) Step(frame int)                    { s.steps++ }
+func (s *solid) Cell(x, y int) (rune, tcell.Style) { return s.ch, s.style }
+func (s *solid) HandleInput(ev tcell.Event) bool   { return true }
+
+func TestCrossFade(t *testing.T) {
+	red := tcell.StyleDefault.Foreground(tcell.NewRGBColor(200, 0, 0))
+	blue := tcell.StyleDefault.Foreground(tcell.NewRGBColor(0, 0, 200))
+	from, to := &solid{ch: 'a', style: red}, &solid{ch: 'b', style: blue}
+	c := newCrossFade(from, to, 4)
+
+	if ch, style := c.Cell(0, 0); ch != 'a' || style != red {
+		t.Fatalf("fade should start on the outgoing visualization")
+	}
+	c.Step(0)
+	c.Step(1)
+	ch, style := c.Cell(0, 0)
+	fg, _, _ := style.Decompose()
+	if r, _, b := fg.RGB(); ch != 'b' || r != 100 || b != 100 {
+		t.Fatalf("halfway: %q %v, want the incoming glyph in an even blend", ch, fg)
+	}
+	c.Step(2)
+	c.Step(3)
+	if !c.done() {
+		t.Fatalf("expected the fade to be done after 4 steps")
+	}
+	if _, style := c.Cell(0, 0); style != blue {
+		t.Fatalf("fade should end on the incoming visualization")
+	}
+	if from.steps != 4 || to.steps != 4 {
+		t.Fatalf("both visualizations should keep running, got %d and %d steps", from.steps, to.steps)
+	}
+}
+
+func TestCrossFade_Intensity(t *testing.T) {
+	to := newFireVisualization(firePalette(), visOptions{seed: 1})
+	to.Resize(20, 10)
+	var vis Visualization = newCrossFade(&solid{ch: 'a'}, to, 4)
+	ic, ok := vis.(intensityControl)
+	if !ok {
+		t.Fatalf("a cross-fade should take intensity changes")
+	}
+	ic.SetIntensity(to.Intensity() + intensityStep)
+	if got := to.Intensity(); got != ic.Intensity() || got == defaultIntensity {
+		t.Fatalf("incoming intensity = %d, cross-fade reports %d", got, ic.Intensity())
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Each cell becomes a cellW×cellH block in PNG screenshots.
+const (
+	screenshotCellW = 4
+	screenshotCellH = 8
+)
+
+// saveScreenshot writes what is currently on screen to
+// ~/Pictures/yule-log-<timestamp>.ansi, plus a .png rendering, and returns
+// the path of the .ansi file. Existing files are never overwritten.
+func saveScreenshot(s tcell.Screen, now time.Time) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, "Pictures")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	stamp := filepath.Join(dir, "yule-log-"+now.Format("20060102-150405"))
+
+	// Two screenshots in the same second get -2, -3... rather than
+	// overwriting each other.
+	for n := 1; ; n++ {
+		base := stamp
+		if n > 1 {
+			base = fmt.Sprintf("%s-%d", stamp, n)
+		}
+		err := writeFile(base+".ansi", func(w *bufio.Writer) error {
+			return writeANSI(w, s)
+		})
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := writeFile(base+".png", func(w *bufio.Writer) error {
+			return png.Encode(w, screenImage(s))
+		}); err != nil {
+			return "", err
+		}
+		return base + ".ansi", nil
+	}
+}
+
+// writeFile creates path, which must not exist yet, and fills it in with
+// write.
+func writeFile(path string, write func(*bufio.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeANSI dumps the screen as text with truecolor SGR escapes, so it can
+// be replayed with `cat`.
+func writeANSI(w *bufio.Writer, s tcell.Screen) error {
+	width, height := s.Size()
+	for y := 0; y < height; y++ {
+		var last tcell.Style
+		first := true
+		for x := 0; x < width; x++ {
+			ch, _, style, _ := s.GetContent(x, y)
+			if first || style != last {
+				w.WriteString(sgr(style))
+				last, first = style, false
+			}
+			w.WriteRune(ch)
+		}
+		if _, err := w.WriteString("\x1b[0m\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sgr returns the escape sequence selecting style.
+func sgr(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	seq := "\x1b[0"
+	if attrs&tcell.AttrBold != 0 {
+		seq += ";1"
+	}
+	if attrs&tcell.AttrDim != 0 {
+		seq += ";2"
+	}
+	if fg != tcell.ColorDefault {
+		r, g, b := fg.RGB()
+		seq += fmt.Sprintf(";38;2;%d;%d;%d", r, g, b)
+	}
+	if bg != tcell.ColorDefault {
+		r, g, b := bg.RGB()
+		seq += fmt.Sprintf(";48;2;%d;%d;%d", r, g, b)
+	}
+	return seq + "m"
+}
+
+// screenImage renders the screen as blocks of color: each non-blank cell
+// is filled with its foreground color, blank cells with its background (or
+// black).
+func screenImage(s tcell.Screen) image.Image {
+	width, height := s.Size()
+	img := image.NewRGBA(image.Rect(0, 0, width*screenshotCellW, height*screenshotCellH))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ch, _, style, _ := s.GetContent(x, y)
+			fg, bg, _ := style.Decompose()
+			c := bg
+			if ch != ' ' && ch != 0 {
+				c = fg
+			}
+			fill := color.RGBA{A: 255}
+			if c != tcell.ColorDefault {
+				r, g, b := c.RGB()
+				fill = color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
+			}
+			for py := 0; py < screenshotCellH; py++ {
+				for px := 0; px < screenshotCellW; px++ {
+					img.SetRGBA(x*screenshotCellW+px, y*screenshotCellH+py, fill)
+				}
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestWriteANSIAndImage(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(3, 2)
+	red := tcell.StyleDefault.Foreground(tcell.NewRGBColor(200, 10, 20)).Bold(true)
+	s.SetContent(1, 0, '#', nil, red)
+	s.Show()
+
+	var sb strings.Builder
+	w := bufio.NewWriter(&sb)
+	if err := writeANSI(w, s); err != nil {
+		t.Fatalf("writeANSI: %v", err)
+	}
+	w.Flush()
+	out := sb.String()
+	if !strings.Contains(out, "\x1b[0;1;38;2;200;10;20m#") {
+		t.Fatalf("expected truecolor bold '#', got %q", out)
+	}
+	if got := strings.Count(out, "\n"); got != 2 {
+		t.Fatalf("expected 2 lines, got %d", got)
+	}
+
+	img := screenImage(s)
+	if b := img.Bounds(); b.Dx() != 3*screenshotCellW || b.Dy() != 2*screenshotCellH {
+		t.Fatalf("image bounds = %v", b)
+	}
+	if r, g, b, _ := img.At(screenshotCellW, 0).RGBA(); r>>8 != 200 || g>>8 != 10 || b>>8 != 20 {
+		t.Fatalf("cell color = %d,%d,%d, want 200,10,20", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestSaveScreenshot_SameSecond(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(3, 2)
+
+	now := time.Date(2026, 12, 24, 21, 0, 0, 0, time.UTC)
+	first, err := saveScreenshot(s, now)
+	if err != nil {
+		t.Fatalf("first screenshot: %v", err)
+	}
+	second, err := saveScreenshot(s, now.Add(300*time.Millisecond))
+	if err != nil {
+		t.Fatalf("second screenshot: %v", err)
+	}
+	if first == second {
+		t.Fatalf("both screenshots saved to %s", first)
+	}
+	if !strings.HasSuffix(second, "-2.ansi") {
+		t.Errorf("second screenshot = %s, want a -2 suffix", second)
+	}
+	for _, path := range []string{first, second, strings.TrimSuffix(second, ".ansi") + ".png"} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("missing %s: %v", path, err)
+		}
+	}
+}
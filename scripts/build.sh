@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+# Release build for cli/gh-extension-precompile (build_script_override):
+# cross-compiles into dist/ with the version, commit and build date
+# stamped in, so `gh yule-log version` reports the release.
+set -euo pipefail
+
+tag="${1:?usage: scripts/build.sh <tag>}"
+commit="$(git rev-parse --short HEAD)"
+date="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+ldflags="-s -w -X main.version=${tag} -X main.commit=${commit} -X main.buildDate=${date}"
+
+platforms=(
+  darwin-amd64
+  darwin-arm64
+  freebsd-386
+  freebsd-amd64
+  freebsd-arm64
+  linux-386
+  linux-amd64
+  linux-arm
+  linux-arm64
+  windows-386
+  windows-amd64
+  windows-arm64
+)
+
+mkdir -p dist
+for p in "${platforms[@]}"; do
+  goos="${p%-*}"
+  goarch="${p#*-}"
+  ext=""
+  if [ "$goos" = windows ]; then
+    ext=".exe"
+  fi
+  GOOS="$goos" GOARCH="$goarch" CGO_ENABLED=0 go build -trimpath -ldflags "$ldflags" -o "dist/${p}${ext}" .
+done
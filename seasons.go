@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --theme auto picks a theme from the date. Rules map a day ("12-31") or
+// an inclusive range of days ("12-01..02-29", which may wrap past the end
+// of the year) to a theme. The most specific matching rule wins, and rules
+// from the [auto-theme] section of the config win ties with the built-in
+// ones, so they can be overridden or extended:
+//
+//	[auto-theme]
+//	03-17 = "contribs"
+//	12-31 = "fire"
+
+// defaultSeasons are the built-in auto-theme rules.
+var defaultSeasons = [][2]string{
+	{"12-31", "fireworks"},
+	{"01-01", "fireworks"},
+	{"07-04", "fireworks"},
+	{"10-20..10-31", "pumpkin"},
+	{"12-01..02-29", "snow"},
+}
+
+// autoFallback is the theme when no rule matches.
+const autoFallback = "fire"
+
+// monthDay is a day of the year.
+type monthDay struct {
+	month time.Month
+	day   int
+}
+
+// before reports whether d comes earlier in the year than o.
+func (d monthDay) before(o monthDay) bool {
+	return d.month < o.month || d.month == o.month && d.day < o.day
+}
+
+// seasonRule maps the days from..to to a theme.
+type seasonRule struct {
+	from, to monthDay
+	theme    string
+}
+
+func parseMonthDay(s string) (monthDay, error) {
+	var m, d int
+	if _, err := fmt.Sscanf(s, "%d-%d", &m, &d); err != nil || len(s) != 5 {
+		return monthDay{}, fmt.Errorf("%q is not a MM-DD date", s)
+	}
+	// A leap year, so 02-29 is allowed.
+	t := time.Date(2024, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if m < 1 || m > 12 || t.Day() != d {
+		return monthDay{}, fmt.Errorf("%q is not a MM-DD date", s)
+	}
+	return monthDay{time.Month(m), d}, nil
+}
+
+// parseSeasonRule parses a "MM-DD" or "MM-DD..MM-DD" key and its theme.
+func parseSeasonRule(days, theme string) (seasonRule, error) {
+	fromStr, toStr, isRange := strings.Cut(days, "..")
+	if !isRange {
+		toStr = fromStr
+	}
+	from, err := parseMonthDay(strings.TrimSpace(fromStr))
+	if err != nil {
+		return seasonRule{}, err
+	}
+	to, err := parseMonthDay(strings.TrimSpace(toStr))
+	if err != nil {
+		return seasonRule{}, err
+	}
+	if _, ok := themeIndex(theme); !ok {
+		return seasonRule{}, fmt.Errorf("%s: unknown theme %q", days, theme)
+	}
+	return seasonRule{from: from, to: to, theme: theme}, nil
+}
+
+// contains reports whether d falls within the rule's days.
+func (r seasonRule) contains(d monthDay) bool {
+	if r.to.before(r.from) {
+		// Wraps past the end of the year.
+		return !d.before(r.from) || !r.to.before(d)
+	}
+	return !d.before(r.from) && !r.to.before(d)
+}
+
+// days is how many days the rule covers.
+func (r seasonRule) days() int {
+	doy := func(d monthDay) int { return time.Date(2024, d.month, d.day, 0, 0, 0, 0, time.UTC).YearDay() }
+	n := doy(r.to) - doy(r.from) + 1
+	if n <= 0 {
+		n += 366
+	}
+	return n
+}
+
+// seasonRules returns the config's [auto-theme] rules followed by the
+// built-in ones.
+func seasonRules(cfg config) ([]seasonRule, error) {
+	var rules []seasonRule
+	var keys []string
+	for days := range cfg["auto-theme"] {
+		keys = append(keys, days)
+	}
+	sort.Strings(keys)
+	for _, days := range keys {
+		r, err := parseSeasonRule(days, cfg["auto-theme"][days])
+		if err != nil {
+			return nil, fmt.Errorf("auto-theme: %w", err)
+		}
+		rules = append(rules, r)
+	}
+	for _, d := range defaultSeasons {
+		r, err := parseSeasonRule(d[0], d[1])
+		if err != nil {
+			panic(err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// autoTheme returns the theme for the date of now: that of the shortest
+// matching rule, preferring earlier rules on a tie.
+func autoTheme(rules []seasonRule, now time.Time) string {
+	today := monthDay{now.Month(), now.Day()}
+	theme, best := autoFallback, 0
+	for _, r := range rules {
+		if r.contains(today) && (best == 0 || r.days() < best) {
+			theme, best = r.theme, r.days()
+		}
+	}
+	return theme
+}
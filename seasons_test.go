@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAutoTheme_Defaults(t *testing.T) {
+	rules, err := seasonRules(config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for date, want := range map[string]string{
+		"2025-12-31": "fireworks",
+		"2026-01-01": "fireworks",
+		"2026-01-02": "snow",
+		"2024-02-29": "snow",
+		"2026-03-01": "fire",
+		"2026-07-04": "fireworks",
+		"2026-10-31": "pumpkin",
+		"2026-11-01": "fire",
+		"2026-12-01": "snow",
+	} {
+		now, _ := time.Parse("2006-01-02", date)
+		if got := autoTheme(rules, now); got != want {
+			t.Errorf("%s: got %q, want %q", date, got, want)
+		}
+	}
+}
+
+func TestAutoTheme_ConfigOverrides(t *testing.T) {
+	cfg, err := parseConfig(strings.NewReader(`
+[auto-theme]
+12-31 = "fire"
+03-01..03-31 = "contribs"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := seasonRules(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for date, want := range map[string]string{
+		"2025-12-31": "fire",
+		"2026-01-01": "fireworks",
+		"2026-03-17": "contribs",
+	} {
+		now, _ := time.Parse("2006-01-02", date)
+		if got := autoTheme(rules, now); got != want {
+			t.Errorf("%s: got %q, want %q", date, got, want)
+		}
+	}
+}
+
+func TestParseSeasonRule_Errors(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"13-01", "fire"},
+		{"02-30", "fire"},
+		{"12-1", "fire"},
+		{"12-01..xx", "fire"},
+		{"12-01", "lava"},
+	} {
+		if _, err := parseSeasonRule(tc[0], tc[1]); err == nil {
+			t.Errorf("parseSeasonRule(%q, %q): expected an error", tc[0], tc[1])
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+import "os"
+
+// stopSignals are the signals that mean "clean up and quit". Windows only
+// delivers Ctrl-C (and Ctrl-Break) as os.Interrupt.
+var stopSignals = []os.Signal{os.Interrupt}
@@ -0,0 +1,10 @@
+package main
+
+import "net"
+
+// listenUnix listens on a Unix socket at path. Windows has no umask or
+// mode bits; the socket gets the ACL of its directory, which is the
+// user's own.
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// soundRate is the sample rate of the generated audio (mono, signed 16-bit).
+const soundRate = 22050
+
+// audioPlayers are tried in order; the first one installed plays raw PCM
+// from stdin. If none is, macOS falls back to afplay and Windows to
+// PowerShell (see openAudioSink).
+var audioPlayers = [][]string{
+	{"paplay", "--raw", "--format=s16le", "--rate=22050", "--channels=1", "--latency-msec=100"},
+	{"pw-cat", "--playback", "--format=s16", "--rate=22050", "--channels=1", "--latency=100ms", "-"},
+	{"aplay", "-q", "-t", "raw", "-f", "S16_LE", "-r", "22050", "-c", "1"},
+	{"play", "-q", "-t", "raw", "-e", "signed", "-b", "16", "-r", "22050", "-c", "1", "-"},
+}
+
+// crackleSynth generates fireplace crackle: a low rumble plus random clicks
+// whose rate and loudness follow the fire's heat.
+type crackleSynth struct {
+	rng    *rand.Rand
+	rumble float64 // low-passed noise state
+	// The current click: remaining samples and amplitude.
+	clickLeft int
+	clickAmp  float64
+}
+
+func newCrackleSynth(seed int64) *crackleSynth {
+	return &crackleSynth{rng: rand.New(rand.NewSource(seed))}
+}
+
+// fill writes len(buf) samples for heat level (0..1). pops extra loud
+// clicks are mixed in at random points.
+func (c *crackleSynth) fill(buf []int16, level float64, pops int) {
+	popAt := map[int]bool{}
+	for i := 0; i < pops; i++ {
+		popAt[c.rng.Intn(len(buf))] = true
+	}
+	// Clicks per second scale with the heat.
+	clickChance := (2 + 25*level) / soundRate
+	for i := range buf {
+		c.rumble += (c.rng.Float64()*2 - 1 - c.rumble) * 0.02
+		v := c.rumble * (0.15 + 0.35*level)
+
+		switch {
+		case popAt[i]:
+			c.clickLeft = 200 + c.rng.Intn(300)
+			c.clickAmp = 0.9
+		case c.clickLeft == 0 && c.rng.Float64() < clickChance:
+			c.clickLeft = 20 + c.rng.Intn(120)
+			c.clickAmp = (0.2 + 0.6*c.rng.Float64()) * (0.3 + 0.7*level)
+		}
+		if c.clickLeft > 0 {
+			v += (c.rng.Float64()*2 - 1) * c.clickAmp
+			c.clickAmp *= 0.97
+			c.clickLeft--
+		}
+
+		buf[i] = int16(math.Max(-1, math.Min(1, v)) * math.MaxInt16 * 0.8)
+	}
+}
+
+// crackle plays a crackleSynth through an external audio player.
+type crackle struct {
+	mu    sync.Mutex
+	level float64
+	pops  int
+
+	sink  audioSink
+	chunk int // samples generated per write
+	done  chan struct{}
+}
+
+// startCrackle starts playing in the background. It returns nil if no
+// audio player could be started.
+func startCrackle() *crackle {
+	sink, chunk := openAudioSink()
+	if sink == nil {
+		return nil
+	}
+	c := &crackle{sink: sink, chunk: chunk, done: make(chan struct{})}
+	go c.run(newCrackleSynth(time.Now().UnixNano()))
+	return c
+}
+
+// run streams audio until the player goes away or stop is called. Writes
+// block until the player is ready for more, which paces generation.
+func (c *crackle) run(synth *crackleSynth) {
+	defer close(c.done)
+	buf := make([]int16, c.chunk)
+	for {
+		c.mu.Lock()
+		level, pops := c.level, c.pops
+		c.pops = 0
+		c.mu.Unlock()
+
+		synth.fill(buf, level, pops)
+		if err := c.sink.write(buf); err != nil {
+			return
+		}
+	}
+}
+
+// setLevel sets the heat (0..1) the crackle follows.
+func (c *crackle) setLevel(level float64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.level = level
+	c.mu.Unlock()
+}
+
+// pop adds a loud crack, e.g. when the fire is stoked.
+func (c *crackle) pop() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.pops++
+	c.mu.Unlock()
+}
+
+// stop silences the player and waits for it to exit.
+func (c *crackle) stop() {
+	if c == nil {
+		return
+	}
+	c.sink.close()
+	<-c.done
+}
+
+// audioSink plays generated samples. write blocks until the player can
+// take more; close stops playback and unblocks any pending write.
+type audioSink interface {
+	write(buf []int16) error
+	close()
+}
+
+// openAudioSink starts the first available player and returns it with the
+// number of samples to hand it per write. Players that read raw PCM from
+// stdin are preferred; macOS and Windows ship none, so there the audio is
+// written out as short WAV files and played one after another with afplay
+// or PowerShell's SoundPlayer.
+func openAudioSink() (audioSink, int) {
+	for _, argv := range audioPlayers {
+		if _, err := exec.LookPath(argv[0]); err != nil {
+			continue
+		}
+		if sink, err := startPipeSink(argv); err == nil {
+			return sink, soundRate / 20
+		}
+	}
+	var start func() (wavPlayer, error)
+	switch fallbackAudioPlayer(runtime.GOOS) {
+	case "afplay":
+		start = startAfplay
+	case "powershell":
+		start = startSoundPlayer
+	default:
+		return nil, 0
+	}
+	if sink, err := startWAVSink(start); err == nil {
+		return sink, soundRate
+	}
+	return nil, 0
+}
+
+// fallbackAudioPlayer names the WAV file player used on goos when none of
+// audioPlayers is installed, or "" if there isn't one.
+func fallbackAudioPlayer(goos string) string {
+	switch goos {
+	case "darwin":
+		return "afplay"
+	case "windows":
+		return "powershell"
+	}
+	return ""
+}
+
+// pipeSink streams raw PCM into a player's stdin.
+type pipeSink struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+}
+
+func startPipeSink(argv []string) (*pipeSink, error) {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &pipeSink{cmd: cmd, in: in}, nil
+}
+
+func (p *pipeSink) write(buf []int16) error {
+	return binary.Write(p.in, binary.LittleEndian, buf)
+}
+
+func (p *pipeSink) close() {
+	p.in.Close()
+	p.cmd.Process.Kill()
+	p.cmd.Wait()
+}
+
+// wavPlayer plays WAV files. play starts a file and returns a function
+// that waits for it to finish.
+type wavPlayer interface {
+	play(path string) (wait func() error, err error)
+	close()
+}
+
+// wavSink writes each chunk to a WAV file and queues it on a wavPlayer.
+// The next chunk is generated while the current one plays.
+type wavSink struct {
+	player  wavPlayer
+	dir     string
+	n       int
+	pending func() error
+}
+
+func startWAVSink(start func() (wavPlayer, error)) (*wavSink, error) {
+	player, err := start()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "yule-log-sound")
+	if err != nil {
+		player.close()
+		return nil, err
+	}
+	return &wavSink{player: player, dir: dir}, nil
+}
+
+func (w *wavSink) write(buf []int16) error {
+	// Two files: one playing, one being written.
+	path := filepath.Join(w.dir, fmt.Sprintf("crackle%d.wav", w.n%2))
+	w.n++
+	if err := writeWAV(path, buf); err != nil {
+		return err
+	}
+	if w.pending != nil {
+		if err := w.pending(); err != nil {
+			return err
+		}
+	}
+	wait, err := w.player.play(path)
+	if err != nil {
+		return err
+	}
+	w.pending = wait
+	return nil
+}
+
+func (w *wavSink) close() {
+	w.player.close()
+	os.RemoveAll(w.dir)
+}
+
+// writeWAV writes mono 16-bit samples at soundRate as a WAV file.
+func writeWAV(path string, buf []int16) error {
+	size := uint32(2 * len(buf))
+	header := struct {
+		RIFF          [4]byte
+		ChunkSize     uint32
+		WAVE, Fmt     [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		Rate          uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		RIFF: [4]byte{'R', 'I', 'F', 'F'}, ChunkSize: 36 + size,
+		WAVE: [4]byte{'W', 'A', 'V', 'E'}, Fmt: [4]byte{'f', 'm', 't', ' '},
+		FmtSize: 16, Format: 1, Channels: 1,
+		Rate: soundRate, ByteRate: 2 * soundRate, BlockAlign: 2, BitsPerSample: 16,
+		Data: [4]byte{'d', 'a', 't', 'a'}, DataSize: size,
+	}
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, header)
+	binary.Write(&b, binary.LittleEndian, buf)
+	return os.WriteFile(path, b.Bytes(), 0o600)
+}
+
+// afplay plays each file with macOS's afplay.
+type afplay struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	closed bool
+}
+
+func startAfplay() (wavPlayer, error) {
+	if _, err := exec.LookPath("afplay"); err != nil {
+		return nil, err
+	}
+	return &afplay{}, nil
+}
+
+func (a *afplay) play(path string) (func() error, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil, os.ErrClosed
+	}
+	cmd := exec.Command("afplay", path)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	a.cmd = cmd
+	return cmd.Wait, nil
+}
+
+func (a *afplay) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	if a.cmd != nil {
+		a.cmd.Process.Kill()
+	}
+}
+
+// soundPlayerScript reads WAV paths from stdin, plays each with
+// System.Media.SoundPlayer and prints a line when it finishes.
+const soundPlayerScript = `$p = New-Object System.Media.SoundPlayer
+while (($f = [Console]::In.ReadLine()) -ne $null) {
+	$p.SoundLocation = $f
+	$p.PlaySync()
+	[Console]::Out.WriteLine('done')
+}`
+
+// soundPlayer plays files through one long-running PowerShell on Windows.
+type soundPlayer struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	out *bufio.Reader
+}
+
+func startSoundPlayer() (wavPlayer, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", soundPlayerScript)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &soundPlayer{cmd: cmd, in: in, out: bufio.NewReader(out)}, nil
+}
+
+func (p *soundPlayer) play(path string) (func() error, error) {
+	if _, err := fmt.Fprintln(p.in, path); err != nil {
+		return nil, err
+	}
+	return func() error {
+		_, err := p.out.ReadString('\n')
+		return err
+	}, nil
+}
+
+func (p *soundPlayer) close() {
+	p.in.Close()
+	p.cmd.Process.Kill()
+	p.cmd.Wait()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func energy(buf []int16) float64 {
+	var e float64
+	for _, v := range buf {
+		e += float64(v) * float64(v)
+	}
+	return e / float64(len(buf))
+}
+
+func TestCrackleSynth_FollowsHeat(t *testing.T) {
+	buf := make([]int16, soundRate)
+
+	newCrackleSynth(1).fill(buf, 0, 0)
+	quiet := energy(buf)
+	newCrackleSynth(1).fill(buf, 1, 0)
+	loud := energy(buf)
+	if loud <= quiet {
+		t.Fatalf("expected a hotter fire to be louder: quiet=%v loud=%v", quiet, loud)
+	}
+
+	newCrackleSynth(1).fill(buf, 0, 5)
+	if popped := energy(buf); popped <= quiet {
+		t.Fatalf("expected pops to add energy: quiet=%v popped=%v", quiet, popped)
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	samples := []int16{0, 1000, -1000, 32767}
+	if err := writeWAV(path, samples); err != nil {
+		t.Fatalf("writeWAV: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 44+2*len(samples) {
+		t.Fatalf("file is %d bytes, want %d", len(b), 44+2*len(samples))
+	}
+	if string(b[0:4]) != "RIFF" || string(b[8:16]) != "WAVEfmt " || string(b[36:40]) != "data" {
+		t.Fatalf("bad header: %q", b[:44])
+	}
+	if rate := binary.LittleEndian.Uint32(b[24:28]); rate != soundRate {
+		t.Errorf("sample rate = %d, want %d", rate, soundRate)
+	}
+	if last := int16(binary.LittleEndian.Uint16(b[len(b)-2:])); last != 32767 {
+		t.Errorf("last sample = %d", last)
+	}
+}
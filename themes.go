@@ -0,0 +1,50 @@
+package main
+
+import "strings"
+
+// theme is a named look the screensaver can switch to.
+type theme struct {
+	name string
+	// layout names where the fire's heat sources sit (see
+	// fire.LayoutNames); --source-layout overrides it.
+	layout string
+	// newVisualization returns fresh state for the theme; the caller
+	// resizes it to the screen.
+	newVisualization func(opts visOptions) Visualization
+}
+
+// visOptions is what a theme needs to know to build its visualization.
+type visOptions struct {
+	seed   int64     // source of any randomness
+	colors colorMode // what the terminal can display
+	layout string    // heat source layout, one of fire.LayoutNames
+}
+
+// themes lists the available themes in the order the theme-cycle key
+// steps through them.
+var themes = []theme{
+	{name: "fire", layout: "random", newVisualization: func(opts visOptions) Visualization { return newFireVisualization(firePalette(), opts) }},
+	{name: "contribs", layout: "random", newVisualization: func(opts visOptions) Visualization { return newFireVisualization(contribsPalette(), opts) }},
+	{name: "snow", layout: "logs", newVisualization: func(opts visOptions) Visualization { return newSnowVisualization(opts) }},
+	{name: "fireworks", layout: "random", newVisualization: func(opts visOptions) Visualization { return newFireworksVisualization(opts) }},
+	{name: "pumpkin", layout: "center", newVisualization: func(opts visOptions) Visualization { return newFireVisualization(pumpkinPalette(), opts) }},
+}
+
+// themeIndex returns the position of the named theme in themes.
+func themeIndex(name string) (int, bool) {
+	for i, t := range themes {
+		if t.name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// themeNames lists the theme names, for messages.
+func themeNames() string {
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// tickerCard is one entry in the ticker: a message over a meta line.
+type tickerCard struct {
+	msg, meta string
+	// style overrides the ticker's style for this card unless it's the
+	// zero style.
+	style tcell.Style
+	// author and when are what the card is about, where known.
+	author string
+	when   time.Time
+}
+
+// layoutCards lays cards out side by side as the two ticker rows, with the
+// style of every column.
+func layoutCards(cards []tickerCard) (msg, meta string, styles []tcell.Style) {
+	var msgSegs, metaSegs []string
+	for _, c := range cards {
+		// Fixed card width so message/meta line up as columns.
+		// Use rune counts so multi-byte characters don't break alignment.
+		segmentWidth := max(len([]rune(c.msg)), len([]rune(c.meta))) + 4
+		msgSegs = append(msgSegs, padRight(c.msg, segmentWidth))
+		metaSegs = append(metaSegs, padRight(c.meta, segmentWidth))
+		for i := 0; i < segmentWidth; i++ {
+			styles = append(styles, c.style)
+		}
+	}
+	return strings.Join(msgSegs, ""), strings.Join(metaSegs, ""), styles
+}
+
+// loadTickerCards returns the cards for --ticker-source: commits,
+// releases or blame, or several joined with "+" and ordered newest first.
+func loadTickerCards(sources, blameFile string) ([]tickerCard, error) {
+	parts, err := tickerSources(sources, blameFile)
+	if err != nil {
+		return nil, err
+	}
+	var cards []tickerCard
+	for _, source := range parts {
+		switch source {
+		case "commits":
+			cards = append(cards, gitLogCards(20)...)
+		case "releases":
+			cards = append(cards, releaseCards(gitDir(), 10)...)
+		case "blame":
+			blamed, err := blameTickerCards(gitDir(), blameFile)
+			if err != nil {
+				return nil, err
+			}
+			cards = append(cards, blamed...)
+		}
+	}
+	if len(parts) > 1 {
+		sort.SliceStable(cards, func(i, j int) bool { return cards[i].when.After(cards[j].when) })
+	}
+	return cards, nil
+}
+
+// tickerSources splits and checks a --ticker-source value without
+// loading anything.
+func tickerSources(sources, blameFile string) ([]string, error) {
+	parts := strings.Split(sources, "+")
+	for _, source := range parts {
+		switch source {
+		case "commits", "releases":
+		case "blame":
+			if blameFile == "" {
+				return nil, fmt.Errorf("--ticker-source blame needs --file")
+			}
+		default:
+			return nil, fmt.Errorf("unknown --ticker-source %q (want commits, releases or blame)", source)
+		}
+	}
+	return parts, nil
+}
+
+// ticker scrolls the commit message and meta lines along the bottom two
+// rows of the screen.
+type ticker struct {
+	msg, meta []rune
+	styles    []tcell.Style // per column of msg; zero means style
+	offset    int
+	style     tcell.Style
+}
+
+func newTicker(cards []tickerCard) *ticker {
+	msg, meta, styles := layoutCards(cards)
+	return &ticker{
+		msg:    []rune(msg),
+		meta:   []rune(meta),
+		styles: styles,
+		style:  tcell.StyleDefault.Foreground(tcell.ColorWhite),
+	}
+}
+
+// restyle recolors the ticker from cards with the same text, keeping its
+// scroll position.
+func (t *ticker) restyle(cards []tickerCard) {
+	_, _, t.styles = layoutCards(cards)
+}
+
+// draw renders the ticker as two aligned lines at the bottom of the screen.
+func (t *ticker) draw(s tcell.Screen, cache *frameCache, width, height int) {
+	msgLen, metaLen := len(t.msg), len(t.meta)
+	if height < 2 || msgLen == 0 || metaLen == 0 {
+		return
+	}
+	msgRow, metaRow := height-2, height-1
+	for x := 0; x < width; x++ {
+		i := (t.offset + x) % msgLen
+		style := t.style
+		if i < len(t.styles) && t.styles[i] != (tcell.Style{}) {
+			style = t.styles[i]
+		}
+		cache.set(s, x, msgRow, t.msg[i], style)
+		cache.set(s, x, metaRow, t.meta[(t.offset+x)%metaLen], style)
+	}
+}
+
+// seek scrolls the ticker to where it would be after n advances.
+func (t *ticker) seek(n int) {
+	if len(t.msg) > 0 {
+		t.offset = n % len(t.msg)
+	}
+}
+
+// advance scrolls the ticker one column and reports whether it has come
+// back round to the start.
+func (t *ticker) advance() bool {
+	if len(t.msg) == 0 {
+		return false
+	}
+	t.offset = (t.offset + 1) % len(t.msg)
+	return t.offset == 0
+}
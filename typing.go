@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// --playground is a typing warm-up: every key press counts towards a
+// keystrokes-per-second rate, measured over a sliding window, and the rate
+// sets the flame height. Esc or Ctrl-C quits.
+
+const (
+	// typingWindow is how far back the rate looks.
+	typingWindow = 3 * time.Second
+	// typingMaxKPS is the rate that gives the tallest flames: 12 keys a
+	// second is about 144 words a minute.
+	typingMaxKPS = 12.0
+	// charsPerWord is the usual words-per-minute convention.
+	charsPerWord = 5
+)
+
+// typingMeter tracks the rate of key presses.
+type typingMeter struct {
+	presses []time.Time
+}
+
+// press records a key press at t.
+func (m *typingMeter) press(t time.Time) {
+	m.presses = append(m.presses, t)
+}
+
+// kps returns the keystrokes per second over the window ending at now.
+func (m *typingMeter) kps(now time.Time) float64 {
+	cutoff := now.Add(-typingWindow)
+	i := 0
+	for i < len(m.presses) && m.presses[i].Before(cutoff) {
+		i++
+	}
+	m.presses = m.presses[i:]
+	return float64(len(m.presses)) / typingWindow.Seconds()
+}
+
+// typingIntensity maps a typing rate onto the flame intensity range.
+func typingIntensity(kps float64) int {
+	frac := min(kps/typingMaxKPS, 1)
+	return minHeat + int(frac*float64(maxHeat-minHeat)+0.5)
+}
+
+// drawTypingGauge shows the rate as a needle on a dial in the top-left
+// corner.
+func drawTypingGauge(s tcell.Screen, cache *frameCache, kps float64) {
+	const track = 20
+	needle := int(min(kps/typingMaxKPS, 1)*float64(track-1) + 0.5)
+	dial := []rune(strings.Repeat("─", track))
+	dial[needle] = '●'
+	label := fmt.Sprintf("%4.1f keys/s %4.0f wpm ├%s┤", kps, kps*60/charsPerWord, string(dial))
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	for i, ch := range []rune(" " + label + " ") {
+		cache.set(s, 1+i, 0, ch, style)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTypingMeter(t *testing.T) {
+	var m typingMeter
+	start := time.Unix(1000, 0)
+	// Six keys a second for two seconds.
+	for i := 0; i < 12; i++ {
+		m.press(start.Add(time.Duration(i) * time.Second / 6))
+	}
+	now := start.Add(2 * time.Second)
+	if got := m.kps(now); got != 12/typingWindow.Seconds() {
+		t.Fatalf("kps = %v, want %v", got, 12/typingWindow.Seconds())
+	}
+	// Once the window has passed, the rate falls back to nothing.
+	if got := m.kps(now.Add(typingWindow + time.Second)); got != 0 {
+		t.Fatalf("kps after a pause = %v, want 0", got)
+	}
+}
+
+func TestTypingIntensity(t *testing.T) {
+	if got := typingIntensity(0); got != minHeat {
+		t.Errorf("idle intensity = %d, want %d", got, minHeat)
+	}
+	if got := typingIntensity(typingMaxKPS * 2); got != maxHeat {
+		t.Errorf("flat-out intensity = %d, want %d", got, maxHeat)
+	}
+	if low, high := typingIntensity(3), typingIntensity(6); low >= high {
+		t.Errorf("faster typing should mean taller flames: %d >= %d", low, high)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Build information, set at build time (scripts/build.sh does this for
+// releases) with e.g.
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Anything left unset is filled in from the module build info where
+// possible.
+var (
+	version   = ""
+	commit    = ""
+	buildDate = ""
+)
+
+// latestReleaseURL is the GitHub API endpoint for `version --check`.
+var latestReleaseURL = "https://api.github.com/repos/leereilly/gh-yule-log/releases/latest"
+
+// versionInfo describes the running binary.
+type versionInfo struct {
+	version, commit, date, goVersion string
+}
+
+func currentVersion() versionInfo {
+	v := versionInfo{version: version, commit: commit, date: buildDate, goVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if v.version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			v.version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && v.commit == "":
+				v.commit = s.Value
+				if len(v.commit) > 12 {
+					v.commit = v.commit[:12]
+				}
+			case s.Key == "vcs.time" && v.date == "":
+				v.date = s.Value
+			}
+		}
+	}
+	if v.version == "" {
+		v.version = "dev"
+	}
+	if v.commit == "" {
+		v.commit = "unknown"
+	}
+	if v.date == "" {
+		v.date = "unknown"
+	}
+	return v
+}
+
+func (v versionInfo) String() string {
+	return fmt.Sprintf("gh-yule-log %s (commit %s, built %s, %s)", v.version, v.commit, v.date, v.goVersion)
+}
+
+// runVersion implements `yule-log version`.
+func runVersion(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	check := fs.Bool("check", false, "Check GitHub for a newer release")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	v := currentVersion()
+	fmt.Fprintln(w, v)
+	if !*check {
+		return nil
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	tag, url, err := latestRelease(client, latestReleaseURL)
+	if err != nil {
+		return fmt.Errorf("checking for updates: %w", err)
+	}
+	if _, ok := parseSemver(v.version); !ok {
+		fmt.Fprintf(w, "The latest release is %s; this build's version is unknown, so it can't be compared.\n", tag)
+	} else if newerVersion(tag, v.version) {
+		fmt.Fprintf(w, "A newer release is available: %s\n  %s\n  Upgrade with: gh extension upgrade yule-log\n", tag, url)
+	} else {
+		fmt.Fprintf(w, "You're up to date (latest release is %s).\n", tag)
+	}
+	return nil
+}
+
+// latestRelease returns the tag and page URL of the latest GitHub release.
+func latestRelease(client *http.Client, url string) (tag, htmlURL string, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("GitHub API: %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", "", err
+	}
+	if release.TagName == "" {
+		return "", "", fmt.Errorf("GitHub API: release has no tag")
+	}
+	return release.TagName, release.HTMLURL, nil
+}
+
+// newerVersion reports whether release is a later version than current.
+// A current version that can't be parsed, such as a development build's,
+// is never considered out of date.
+func newerVersion(release, current string) bool {
+	r, ok := parseSemver(release)
+	if !ok {
+		return false
+	}
+	c, ok := parseSemver(current)
+	if !ok {
+		return false
+	}
+	return compareSemver(r, c) > 0
+}
+
+// semver is a parsed version: the numeric major.minor.patch and any
+// dot-separated prerelease identifiers. Build metadata is dropped, since it
+// doesn't affect precedence.
+type semver struct {
+	num [3]int
+	pre []string
+}
+
+// parseSemver parses "v1.2.3" or "1.2.3", with an optional "-pre" and
+// "+build" suffix.
+func parseSemver(s string) (semver, bool) {
+	var out semver
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		pre := s[i+1:]
+		s = s[:i]
+		if pre == "" {
+			return out, false
+		}
+		out.pre = strings.Split(pre, ".")
+		for _, id := range out.pre {
+			if id == "" {
+				return out, false
+			}
+		}
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out.num[i] = n
+	}
+	return out, true
+}
+
+// compareSemver orders a and b by semver precedence, returning -1, 0 or 1.
+// A release sorts above any prerelease of the same version; prerelease
+// identifiers compare numerically when both are numbers, lexically
+// otherwise, with numeric ones sorting first.
+func compareSemver(a, b semver) int {
+	for i := range a.num {
+		if a.num[i] != b.num[i] {
+			return cmp.Compare(a.num[i], b.num[i])
+		}
+	}
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		x, y := a.pre[i], b.pre[i]
+		if x == y {
+			continue
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			return cmp.Compare(xn, yn)
+		case xerr == nil:
+			return -1
+		case yerr == nil:
+			return 1
+		case x < y:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return cmp.Compare(len(a.pre), len(b.pre))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewerVersion(t *testing.T) {
+	for _, tc := range []struct {
+		release, current string
+		want             bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.10.0", "v1.9.0", true},
+		{"v1.2.0", "v2.0.0", false},
+		{"v1.2.0", "dev", false},
+		{"nightly", "v1.0.0", false},
+		{"v1.2.1", "v1.2.1-rc.1", true},
+		{"v1.2.1-rc.1", "v1.2.1", false},
+		{"v1.2.1-rc.2", "v1.2.1-rc.1", true},
+		{"v1.2.1-rc.10", "v1.2.1-rc.9", true},
+		{"v1.2.1-rc.1", "v1.2.1-beta.3", true},
+		{"v1.2.1-rc.1.1", "v1.2.1-rc.1", true},
+		{"v1.2.1+build.5", "v1.2.1", false},
+	} {
+		if got := newerVersion(tc.release, tc.current); got != tc.want {
+			t.Errorf("newerVersion(%q, %q) = %v, want %v", tc.release, tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v1.3.0", "html_url": "https://example.com/r/v1.3.0"}`)
+	}))
+	defer srv.Close()
+
+	tag, url, err := latestRelease(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("latestRelease: %v", err)
+	}
+	if tag != "v1.3.0" || url != "https://example.com/r/v1.3.0" {
+		t.Fatalf("got %q %q", tag, url)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	if _, _, err := latestRelease(missing.Client(), missing.URL); err == nil {
+		t.Fatalf("expected error for 404")
+	}
+}
+
+func TestCurrentVersionString(t *testing.T) {
+	if s := currentVersion().String(); !strings.HasPrefix(s, "gh-yule-log ") || !strings.Contains(s, "go") {
+		t.Fatalf("unexpected version string %q", s)
+	}
+}
+
+func TestRunVersionCheck_DevBuild(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v1.3.0", "html_url": "https://example.com/r/v1.3.0"}`)
+	}))
+	defer srv.Close()
+	defer func(url string) { latestReleaseURL = url }(latestReleaseURL)
+	latestReleaseURL = srv.URL
+
+	var out strings.Builder
+	if err := runVersion([]string{"--check"}, &out); err != nil {
+		t.Fatalf("runVersion: %v", err)
+	}
+	if got := out.String(); strings.Contains(got, "newer release") || !strings.Contains(got, "can't be compared") {
+		t.Errorf("dev build --check output:\n%s", got)
+	}
+}
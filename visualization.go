@@ -0,0 +1,252 @@
+package main
+
+import (
+	"math/rand"
+
+	"github.com/gdamore/tcell/v2"
+
+	"gh-yule-log/internal/fire"
+)
+
+// Visualization is an effect drawn behind the ticker. The main loop steps
+// the active visualization once per frame and then asks it for every
+// visible cell, so new effects only need to implement this interface.
+type Visualization interface {
+	// Resize is called with the screen size at startup and on every resize.
+	Resize(width, height int)
+	// Step advances the effect by one frame.
+	Step(frame int)
+	// Cell returns the glyph and style to draw at x,y.
+	Cell(x, y int) (rune, tcell.Style)
+	// HandleInput offers ev to the visualization and reports whether it
+	// was consumed. Key presses arrive as an *actionEvent for their bound
+	// action; mouse events arrive as-is.
+	HandleInput(ev tcell.Event) bool
+}
+
+// palette maps heat to glyphs and colors. chars is indexed by heat (capped
+// at the last entry); styles[1..4] cover increasing heat bands. A palette
+// prepared with forColors blends between the bands instead of switching
+// abruptly: through gradient if it's set, otherwise by dithering.
+type palette struct {
+	chars    []rune
+	styles   []tcell.Style
+	gradient []tcell.Style
+	dither   bool
+}
+
+// firePalette is the original fire-style glyphs and colors.
+func firePalette() palette {
+	return palette{
+		chars: []rune{' ', '.', ':', '^', '*', 'x', 's', 'S', '#', '$'},
+		// Colors: dark red -> bright yellow/white.
+		styles: []tcell.Style{
+			tcell.StyleDefault.Foreground(tcell.ColorBlack),
+			tcell.StyleDefault.Foreground(tcell.ColorMaroon),
+			tcell.StyleDefault.Foreground(tcell.ColorRed),
+			tcell.StyleDefault.Foreground(tcell.ColorDarkOrange),
+			tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true),
+		},
+	}
+}
+
+// contribsPalette is the GitHub contribution graph-style glyphs and colors.
+func contribsPalette() palette {
+	return palette{
+		// Use varied glyphs for different intensity levels (10 total to match original behavior)
+		chars: []rune{' ', '⬝', '⬝', '⯀', '⯀', '◼', '◼', '■', '■', '■'},
+		// GitHub green palette: empty, low (#9be9a8), medium (#40c463), high (#30a14e), very high (#216e39)
+		styles: []tcell.Style{
+			tcell.StyleDefault.Foreground(tcell.ColorBlack),                 // index 0 (not used by style selection)
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(155, 233, 168)), // #9be9a8 (low)
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(64, 196, 99)),   // #40c463 (medium)
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(48, 161, 78)),   // #30a14e (high)
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(33, 110, 57)),   // #216e39 (very high)
+		},
+	}
+}
+
+// pumpkinPalette is a deep orange palette for Halloween.
+func pumpkinPalette() palette {
+	return palette{
+		chars: []rune{' ', '.', ':', '^', '*', 'x', 's', 'S', '#', '$'},
+		// Colors: burnt brown -> pumpkin -> candlelight.
+		styles: []tcell.Style{
+			tcell.StyleDefault.Foreground(tcell.ColorBlack),
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(92, 40, 0)),
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(190, 80, 0)),
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(255, 117, 24)),
+			tcell.StyleDefault.Foreground(tcell.NewRGBColor(255, 200, 80)).Bold(true),
+		},
+	}
+}
+
+// glyph returns the glyph and style for heat v at x,y.
+func (p palette) glyph(v float64, x, y int) (rune, tcell.Style) {
+	var style tcell.Style
+	switch {
+	case p.gradient != nil:
+		style = p.gradient[int(bandPosition(v)*gradientSteps+0.5)]
+	case p.dither:
+		// Ordered dithering: the fraction of the way to the next band
+		// decides how many cells of each 4×4 block use its style.
+		pos := bandPosition(v)
+		band := int(pos)
+		if band < 3 && pos-float64(band) > (bayer4[y&3][x&3]+0.5)/16 {
+			band++
+		}
+		style = p.styles[band+1]
+	case v > 15:
+		style = p.styles[4]
+	case v > 9:
+		style = p.styles[3]
+	case v > 4:
+		style = p.styles[2]
+	default:
+		style = p.styles[1]
+	}
+	chIdx := int(v)
+	if chIdx >= len(p.chars) {
+		chIdx = len(p.chars) - 1
+	}
+	if chIdx < 0 {
+		chIdx = 0
+	}
+	return p.chars[chIdx], style
+}
+
+// clamp heat values to reasonable ranges.
+const (
+	minHeat          = 10
+	maxHeat          = 85
+	minSources       = 1
+	defaultIntensity = 65
+	intensityStep    = 5
+)
+
+// intensityControl is implemented by visualizations whose flame height can
+// be adjusted, so the main loop can show a gauge and persist the setting.
+type intensityControl interface {
+	Intensity() int
+	SetIntensity(int)
+}
+
+// fireVisualization draws a fire.Simulation through a palette. Its
+// intensity is the heat power of each source; every intensityStep above
+// or below the default also adds or removes a source.
+type fireVisualization struct {
+	sim       *fire.Simulation
+	pal       palette
+	width     int
+	height    int
+	intensity int
+}
+
+func newFireVisualization(pal palette, opts visOptions) *fireVisualization {
+	sim := fire.NewSimulation(0, 0, opts.seed)
+	if layout, ok := fire.LayoutByName(opts.layout); ok {
+		sim.Layout = layout
+	}
+	f := &fireVisualization{sim: sim, pal: pal.forColors(opts.colors)}
+	f.SetIntensity(defaultIntensity)
+	return f
+}
+
+func (f *fireVisualization) Resize(width, height int) {
+	f.width, f.height = width, height
+	f.sim.Resize(width, height)
+	f.applyIntensity()
+}
+
+func (f *fireVisualization) Step(frame int) {
+	f.sim.Step()
+}
+
+func (f *fireVisualization) Cell(x, y int) (rune, tcell.Style) {
+	return f.pal.glyph(f.sim.Cells()[y*f.width+x], x, y)
+}
+
+// Heat returns the simulation's heat at x,y.
+func (f *fireVisualization) Heat(x, y int) float64 {
+	return f.sim.Cells()[y*f.width+x]
+}
+
+func (f *fireVisualization) HandleInput(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *actionEvent:
+		switch ev.action {
+		case actionIntensityUp:
+			f.adjustHeat(1)
+		case actionIntensityDown:
+			f.adjustHeat(-1)
+		case actionStoke:
+			// A burst of full heat across a third of the bottom row.
+			for i := 0; i < f.width/3; i++ {
+				f.sim.Inject(rand.Intn(f.width), maxHeat)
+			}
+		default:
+			return false
+		}
+		return true
+	case *tcell.EventMouse:
+		// Mouse events only arrive when --mouse is set.
+		buttons := ev.Buttons()
+		switch {
+		case buttons&tcell.WheelUp != 0:
+			f.adjustHeat(1)
+		case buttons&tcell.WheelDown != 0:
+			f.adjustHeat(-1)
+		case buttons&tcell.Button1 != 0:
+			// Clicking or dragging stokes the fire under the pointer.
+			x, y := ev.Position()
+			f.stoke(x, y)
+		}
+		return true
+	}
+	return false
+}
+
+// adjustHeat raises (dir > 0) or lowers (dir < 0) the intensity by one
+// step.
+func (f *fireVisualization) adjustHeat(dir int) {
+	f.SetIntensity(f.intensity + dir*intensityStep)
+}
+
+func (f *fireVisualization) Intensity() int {
+	return f.intensity
+}
+
+// SetIntensity sets the heat power, clamped to [minHeat, maxHeat].
+func (f *fireVisualization) SetIntensity(v int) {
+	if v < minHeat {
+		v = minHeat
+	}
+	if v > maxHeat {
+		v = maxHeat
+	}
+	f.intensity = v
+	f.applyIntensity()
+}
+
+// applyIntensity derives the simulation's power and source count from the
+// intensity and width.
+func (f *fireVisualization) applyIntensity() {
+	f.sim.Power = float64(f.intensity)
+	sources := f.width/9 + (f.intensity-defaultIntensity)/intensityStep
+	if sources > f.width {
+		sources = f.width
+	}
+	if sources < minSources {
+		sources = minSources
+	}
+	f.sim.Sources = sources
+}
+
+// stoke drops a small blob of maximum heat at x,y.
+func (f *fireVisualization) stoke(x, y int) {
+	for dy := 0; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			f.sim.InjectAt(x+dx, y+dy, maxHeat)
+		}
+	}
+}
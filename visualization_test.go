@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"gh-yule-log/internal/fire"
+)
+
+func TestPaletteGlyph(t *testing.T) {
+	pal := firePalette()
+	if ch, style := pal.glyph(0, 0, 0); ch != ' ' || style != pal.styles[1] {
+		t.Fatalf("glyph(0) = %q, want blank in the coolest style", ch)
+	}
+	if ch, style := pal.glyph(70, 0, 0); ch != '$' || style != pal.styles[4] {
+		t.Fatalf("glyph(70) = %q, want hottest glyph and style", ch)
+	}
+	if ch, _ := pal.glyph(-3, 0, 0); ch != ' ' {
+		t.Fatalf("glyph(-3) = %q, want blank", ch)
+	}
+}
+
+func TestFireVisualization_IntensityActions(t *testing.T) {
+	f := newFireVisualization(firePalette(), visOptions{seed: 1})
+	f.Resize(90, 20)
+	power, sources := f.sim.Power, f.sim.Sources
+
+	if !f.HandleInput(newActionEvent(actionIntensityUp)) {
+		t.Fatalf("expected intensity-up to be consumed")
+	}
+	if f.sim.Power != power+5 || f.sim.Sources != sources+1 {
+		t.Fatalf("after intensity-up power=%v sources=%d", f.sim.Power, f.sim.Sources)
+	}
+	for i := 0; i < 50; i++ {
+		f.HandleInput(newActionEvent(actionIntensityDown))
+	}
+	if f.sim.Power != minHeat || f.sim.Sources != minSources {
+		t.Fatalf("expected clamping at minimum, got power=%v sources=%d", f.sim.Power, f.sim.Sources)
+	}
+	if f.HandleInput(newActionEvent(actionExit)) {
+		t.Fatalf("expected exit to fall through")
+	}
+}
+
+func TestFireVisualization_MouseStokesAndScrolls(t *testing.T) {
+	f := newFireVisualization(firePalette(), visOptions{seed: 1})
+	f.Resize(20, 10)
+	power := f.sim.Power
+
+	if !f.HandleInput(tcell.NewEventMouse(5, 3, tcell.Button1, tcell.ModNone)) {
+		t.Fatalf("expected click to be consumed")
+	}
+	if got := f.sim.Cells()[3*20+5]; got != maxHeat {
+		t.Fatalf("heat under pointer = %v, want %v", got, maxHeat)
+	}
+	f.HandleInput(tcell.NewEventMouse(5, 3, tcell.WheelUp, tcell.ModNone))
+	if f.sim.Power != power+5 {
+		t.Fatalf("expected wheel up to raise power, got %v", f.sim.Power)
+	}
+}
+
+func TestThemes_Construct(t *testing.T) {
+	for _, th := range themes {
+		vis := th.newVisualization(visOptions{seed: 1, colors: colorsTrue})
+		vis.Resize(10, 4)
+		vis.Step(0)
+		vis.Cell(9, 3)
+		if _, ok := fire.LayoutByName(th.layout); !ok {
+			t.Fatalf("theme %q has unknown layout %q", th.name, th.layout)
+		}
+		if i, ok := themeIndex(th.name); !ok || themes[i].name != th.name {
+			t.Fatalf("themeIndex(%q) = %d, %v", th.name, i, ok)
+		}
+	}
+	if _, ok := themeIndex("nope"); ok {
+		t.Fatalf("expected unknown theme to be missing")
+	}
+}
+
+func TestFireworks_Burst(t *testing.T) {
+	f := newFireworksVisualization(visOptions{seed: 1})
+	f.Resize(60, 30)
+	drawn := false
+	for frame := 0; frame < 400 && !drawn; frame++ {
+		f.Step(frame)
+		for y := 0; y < 15; y++ {
+			for x := 0; x < 60; x++ {
+				// Look at the particle layer itself: the fire palette
+				// draws '*' too.
+				if ch, _, ok := f.layer.at(x, y); ok && ch == '*' {
+					drawn = true
+				}
+			}
+		}
+	}
+	if !drawn {
+		t.Fatalf("expected a burst of sparks in the top half")
+	}
+}
+
+func TestSnow_MeltsInFlames(t *testing.T) {
+	s := newSnowVisualization(visOptions{seed: 1})
+	s.Resize(40, 20)
+	for frame := 0; frame < 200; frame++ {
+		s.Step(frame)
+	}
+	heat := s.sim.Cells()
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if _, _, ok := s.layer.at(x, y); ok && heat[y*40+x] > 4 {
+				t.Fatalf("flake drawn over flames at %d,%d", x, y)
+			}
+		}
+	}
+}
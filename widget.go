@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gh-yule-log/internal/fire"
+)
+
+// Sparkline glyphs from coolest to hottest.
+var sparkGlyphs = []rune("▁▂▃▄▅▆▇█")
+
+// widgetHeight is the height of the tiny simulation behind the sparkline;
+// each row a flame reaches is one sparkline level.
+const widgetHeight = 8
+
+// widgetState is persisted between status-widget runs so the flame keeps
+// moving instead of restarting from cold every time.
+type widgetState struct {
+	Width int       `json:"width"`
+	Cells []float64 `json:"cells"`
+}
+
+// runStatusWidget implements `yule-log status-widget`: print a one-line
+// flame sparkline and the latest commit subject, for tmux's status-right:
+//
+//	set -g status-right '#(cd #{pane_current_path}; gh yule-log status-widget --format tmux)'
+func runStatusWidget(args []string) error {
+	fs := flag.NewFlagSet("status-widget", flag.ContinueOnError)
+	width := fs.Int("width", 8, "Sparkline width in cells")
+	maxSubject := fs.Int("max", 40, "Truncate the commit subject to this many characters (0 hides it)")
+	format := fs.String("format", "ansi", "Color format: ansi (terminals) or tmux (#[fg=...] status-line styles)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *width < 1 {
+		return fmt.Errorf("--width must be at least 1")
+	}
+	if *format != "ansi" && *format != "tmux" {
+		return fmt.Errorf("unknown --format %q", *format)
+	}
+
+	sim := fire.NewSimulation(*width, widgetHeight, time.Now().UnixNano())
+	sim.Power = defaultIntensity
+	sim.Sources = (*width + 2) / 3
+	path, err := widgetStatePath()
+	if err == nil {
+		loadWidgetState(path, sim)
+	}
+	// A few steps per run keeps it lively at tmux's usual status-interval.
+	for i := 0; i < 3; i++ {
+		sim.Step()
+	}
+	if path != "" {
+		saveWidgetState(path, sim)
+	}
+
+	line := renderSparkline(sim, *format)
+	if *maxSubject > 0 {
+		if subject := latestCommitSubject(); subject != "" {
+			subject = truncate(subject, *maxSubject)
+			if *format == "tmux" {
+				// Keep tmux from reading # in the subject as a format.
+				subject = strings.ReplaceAll(subject, "#", "##")
+			}
+			line += " " + subject
+		}
+	}
+	fmt.Println(line)
+	return nil
+}
+
+// renderSparkline draws the flame height of each column as a block glyph
+// colored by its heat.
+func renderSparkline(sim *fire.Simulation, format string) string {
+	w, h := sim.Size()
+	cells := sim.Cells()
+	var b strings.Builder
+	for x := 0; x < w; x++ {
+		// How many rows up from the bottom this column is burning.
+		level := 0
+		for y := h - 1; y >= 0 && cells[y*w+x] > 2; y-- {
+			level++
+		}
+		idx := level - 1
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(sparkGlyphs) {
+			idx = len(sparkGlyphs) - 1
+		}
+		r, g, bl := heatColor(float64(level) / float64(h))
+		if format == "tmux" {
+			fmt.Fprintf(&b, "#[fg=#%02x%02x%02x]%c", r, g, bl, sparkGlyphs[idx])
+		} else {
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm%c", r, g, bl, sparkGlyphs[idx])
+		}
+	}
+	if format == "tmux" {
+		b.WriteString("#[default]")
+	} else {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// heatColor maps t in [0,1] from dark red through orange to yellow.
+func heatColor(t float64) (r, g, b int) {
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	return 128 + int(127*t), int(220 * t * t), 0
+}
+
+func widgetStatePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-yule-log", "status-widget.json"), nil
+}
+
+// loadWidgetState restores the heat field saved by a previous run, if it
+// matches the current size.
+func loadWidgetState(path string, sim *fire.Simulation) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var st widgetState
+	w, _ := sim.Size()
+	if json.Unmarshal(data, &st) != nil || st.Width != w || len(st.Cells) != len(sim.Cells()) {
+		return
+	}
+	copy(sim.Cells(), st.Cells)
+}
+
+// saveWidgetState is best effort: a widget that can't save just restarts
+// cold next time.
+func saveWidgetState(path string, sim *fire.Simulation) {
+	w, _ := sim.Size()
+	data, err := json.Marshal(widgetState{Width: w, Cells: sim.Cells()})
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if os.WriteFile(tmp, data, 0o644) == nil {
+		os.Rename(tmp, path)
+	}
+}
+
+// latestCommitSubject returns the subject of HEAD, or "" outside a repo.
+func latestCommitSubject() string {
+	cmd := exec.Command("git", "log", "-n", "1", "--pretty=format:%s")
+	if dir := os.Getenv("YULE_LOG_GIT_DIR"); dir != "" {
+		cmd.Dir = dir
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	rs := []rune(s)
+	if len(rs) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(rs[:n])
+	}
+	return string(rs[:n-1]) + "…"
+}
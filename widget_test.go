@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gh-yule-log/internal/fire"
+)
+
+func TestRenderSparkline(t *testing.T) {
+	sim := fire.NewSimulation(2, widgetHeight, 1)
+	cells := sim.Cells()
+	// Column 0 burns three rows up from the bottom; column 1 is cold.
+	for y := widgetHeight - 3; y < widgetHeight; y++ {
+		cells[y*2] = 30
+	}
+	out := renderSparkline(sim, "tmux")
+	if !strings.Contains(out, "▃") || !strings.Contains(out, "▁") || !strings.HasSuffix(out, "#[default]") {
+		t.Fatalf("unexpected sparkline %q", out)
+	}
+	if ansi := renderSparkline(sim, "ansi"); !strings.HasPrefix(ansi, "\x1b[38;2;") {
+		t.Fatalf("expected ANSI colors, got %q", ansi)
+	}
+}
+
+func TestWidgetState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	sim := fire.NewSimulation(3, widgetHeight, 1)
+	sim.Cells()[5] = 12
+	saveWidgetState(path, sim)
+
+	restored := fire.NewSimulation(3, widgetHeight, 2)
+	loadWidgetState(path, restored)
+	if got := restored.Cells()[5]; got != 12 {
+		t.Fatalf("restored cell = %v, want 12", got)
+	}
+	other := fire.NewSimulation(4, widgetHeight, 2)
+	loadWidgetState(path, other)
+	if got := other.Cells()[5]; got != 0 {
+		t.Fatalf("expected state for another width to be ignored, got %v", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("hello world", 5); got != "hell…" {
+		t.Fatalf("truncate = %q", got)
+	}
+	if got := truncate("hi", 5); got != "hi" {
+		t.Fatalf("truncate = %q", got)
+	}
+}